@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	html "html/template"
+	"io"
 	"log/slog"
 	"maps"
 	"slices"
@@ -32,16 +33,17 @@ type Aggregate struct {
 }
 
 type Alert struct {
-	Id      string
-	Where   string
-	Tag     string
-	What    string
-	Details string
-	When    time.Time
-	Status  string
-	Links   []html.HTML
-	Labels  map[string]string
-	Silence connectors.SilencerFunc
+	Id        string
+	Where     string
+	Tag       string
+	What      string
+	Details   string
+	When      time.Time
+	Status    string
+	Links     []html.HTML
+	Labels    map[string]string
+	Silence   connectors.SilencerFunc
+	Unsilence connectors.UnsilencerFunc
 }
 
 type AlertGroup struct {
@@ -63,22 +65,35 @@ type Aggregator struct {
 	connectors    []connectors.Connector
 	whereTempl    *text.Template
 	registrations sync.Map
-	cmu           *sync.RWMutex // Protecting Configuration
-	amu           *sync.RWMutex // Protecting current Aggregate
+	cmu           *sync.RWMutex   // Protecting Configuration
+	inflight      *sync.WaitGroup // Tracking uses of the configured connectors
+	amu           *sync.RWMutex   // Protecting current Aggregate
 	current       map[string]Aggregate
 	dashboards    map[string]*config.Dashboard
 	groupAlerts   bool
 
+	rmu        *sync.Mutex // Protecting last results and their aggregation
+	results    []result
+	generation atomic.Uint64 // ordering the collections of the results
+
+	// Streaming connectors announce changes, to be collected immediately
+	pmu         *sync.Mutex // Protecting pending
+	pending     map[connectors.Connector]bool
+	changed     chan struct{}
+	streamCtx   context.Context
+	stopStreams context.CancelFunc
+
 	lastAccess atomic.Value
 
 	CheckTime time.Time
 }
 
 type result struct {
-	tag       string
-	alerts    []connectors.Alert
-	error     error
-	connector connectors.Connector
+	tag        string
+	alerts     []connectors.Alert
+	error      error
+	connector  connectors.Connector
+	generation uint64
 }
 
 var (
@@ -104,7 +119,12 @@ func NewAggregator(cfg *config.Config, clock clock.Clock) *Aggregator {
 
 		registrations: sync.Map{},
 		cmu:           new(sync.RWMutex),
+		inflight:      new(sync.WaitGroup),
 		amu:           new(sync.RWMutex),
+		rmu:           new(sync.Mutex),
+		pmu:           new(sync.Mutex),
+		pending:       make(map[connectors.Connector]bool),
+		changed:       make(chan struct{}, 1),
 
 		clock:  clock,
 		tracer: otel.Tracer("aggregator"),
@@ -147,10 +167,16 @@ func (a *Aggregator) Run(ctx context.Context) {
 	ticker := a.clock.Ticker(a.interval)
 	defer ticker.Stop()
 
+	a.cmu.Lock()
+	a.streamCtx = ctx
+	a.startStreams(ctx)
+	a.cmu.Unlock()
+
 	slog.InfoContext(ctx, "Collecting on Start")
 	collect := make(chan result, 20)
 	go a.collect(ctx, collect)
 	go a.collectAggregate(ctx, collect)
+	go a.refreshChanged(ctx)
 
 	active := true
 	for {
@@ -196,8 +222,139 @@ outer:
 		}
 	}
 
+	a.store(ctx, results, true)
+}
+
+// refreshChanged refreshes the connectors which announced changes, without
+// delaying the regular collections.
+func (a *Aggregator) refreshChanged(ctx context.Context) {
+	for {
+		select {
+		case <-a.changed:
+			if !a.active() {
+				slog.DebugContext(ctx, "Skipping refresh")
+				continue
+			}
+
+			a.refresh(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// store merges the results into the last results, keeping the latest result
+// of every connector, and aggregates them.  A complete collection replaces
+// the set of connectors, otherwise only the given connectors are updated.
+// Collections and refreshes run concurrently, storing is serialized to
+// aggregate them in order.
+func (a *Aggregator) store(ctx context.Context, results []result, complete bool) {
+	a.rmu.Lock()
+	defer a.rmu.Unlock()
+
+	var merged []result
+	if complete {
+		merged = make([]result, 0, len(results))
+		for _, r := range results {
+			if last, ok := a.lastResult(r.connector); ok && last.generation > r.generation {
+				r = last
+			}
+			merged = append(merged, r)
+		}
+	} else {
+		merged = slices.Clone(a.results)
+		for _, r := range results {
+			i := slices.IndexFunc(merged, func(m result) bool { return m.connector == r.connector })
+			if i < 0 {
+				merged = append(merged, r)
+			} else if merged[i].generation < r.generation {
+				merged[i] = r
+			}
+		}
+	}
+	a.results = merged
+
 	for _, dashboard := range a.dashboards {
-		a.aggregate(ctx, dashboard, results)
+		a.aggregate(ctx, dashboard, merged)
+	}
+}
+
+// lastResult returns the last result of the connector.  The caller has to hold
+// the results lock.
+func (a *Aggregator) lastResult(c connectors.Connector) (result, bool) {
+	for _, r := range a.results {
+		if r.connector == c {
+			return r, true
+		}
+	}
+	return result{}, false
+}
+
+// refresh collects the connectors which announced changes and aggregates
+// their results together with the last results of all other connectors.
+func (a *Aggregator) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, a.interval/2)
+	defer cancel()
+
+	a.pmu.Lock()
+	changed := a.pending
+	a.pending = make(map[connectors.Connector]bool)
+	a.pmu.Unlock()
+
+	current, done := a.acquire()
+	defer done()
+
+	var results []result
+	for c := range changed {
+		// Streams of replaced connectors might still have announced changes
+		if !slices.Contains(current, c) {
+			continue
+		}
+
+		generation := a.generation.Add(1)
+		alerts, err := c.Collect(ctx)
+		slog.DebugContext(ctx, "Refreshed alerts",
+			slog.String("collector", c.String()),
+			slog.String("tag", c.Tag()),
+			slog.Int("count", len(alerts)),
+			slog.Any("error", err))
+
+		results = append(results, result{
+			tag:        c.Tag(),
+			alerts:     alerts,
+			error:      err,
+			connector:  c,
+			generation: generation,
+		})
+	}
+
+	a.store(ctx, results, false)
+}
+
+// startStreams runs the streams of all streaming connectors, until they are
+// stopped on reconfiguration.  The caller has to hold the configuration lock.
+func (a *Aggregator) startStreams(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	a.stopStreams = cancel
+
+	for _, c := range a.connectors {
+		if s, ok := c.(connectors.Streamer); ok {
+			slog.DebugContext(ctx, "Starting stream", slog.String("tag", c.Tag()))
+			go s.Stream(ctx, func() { a.announce(c) })
+		}
+	}
+}
+
+// announce marks a connector as changed, to be refreshed as soon as possible.
+// Multiple announcements are coalesced until the refresh happens.
+func (a *Aggregator) announce(c connectors.Connector) {
+	a.pmu.Lock()
+	a.pending[c] = true
+	a.pmu.Unlock()
+
+	select {
+	case a.changed <- struct{}{}:
+	default:
 	}
 }
 
@@ -210,10 +367,13 @@ func (a *Aggregator) collect(ctx context.Context, collect chan<- result) {
 	ctx, cancel := context.WithTimeout(ctx, a.interval/2)
 	defer cancel()
 
-	a.cmu.RLock()
-	for _, c := range a.connectors {
+	current, done := a.acquire()
+	defer done()
+
+	for _, c := range current {
 		slog.DebugContext(ctx, "Adding collection", slog.String("tag", c.Tag()))
 		wg.Go(func() {
+			generation := a.generation.Add(1)
 			alerts, err := c.Collect(ctx)
 
 			// Be graceful on errors accessing the handed-in channel
@@ -229,10 +389,11 @@ func (a *Aggregator) collect(ctx context.Context, collect chan<- result) {
 			}()
 
 			r := result{
-				tag:       c.Tag(),
-				alerts:    alerts,
-				error:     err,
-				connector: c,
+				tag:        c.Tag(),
+				alerts:     alerts,
+				error:      err,
+				connector:  c,
+				generation: generation,
 			}
 			select {
 			case collect <- r:
@@ -243,7 +404,6 @@ func (a *Aggregator) collect(ctx context.Context, collect chan<- result) {
 			}
 		})
 	}
-	a.cmu.RUnlock()
 
 	wg.Wait()
 	slog.DebugContext(ctx, "Collection end")
@@ -292,22 +452,27 @@ func (a *Aggregator) aggregate(ctx context.Context, dashboard *config.Dashboard,
 			}
 
 			alert := Alert{
-				Id:      connectors.RandomAlertId(),
-				Where:   where,
-				Tag:     r.tag,
-				What:    al.Description,
-				Details: al.Details,
-				When:    al.Start,
-				Status:  al.State.String(),
-				Links:   al.Links,
-				Labels:  labels,
-				Silence: al.Silence,
+				Id:        connectors.RandomAlertId(),
+				Where:     where,
+				Tag:       r.tag,
+				What:      al.Description,
+				Details:   al.Details,
+				When:      al.Start,
+				Status:    al.State.String(),
+				Links:     al.Links,
+				Labels:    labels,
+				Silence:   al.Silence,
+				Unsilence: al.Unsilence,
 			}
 
 			if alert.Silence != nil {
 				alert.Links = append(alert.Links,
 					html.HTML(`<form class="txtform" action="/alerts/`+alert.Id+`/silence" method="post"><button class="txtbtn" value="silence" type="submit">🔇</button></form>`))
 			}
+			if alert.Unsilence != nil {
+				alert.Links = append(alert.Links,
+					html.HTML(`<form class="txtform" action="/alerts/`+alert.Id+`/unsilence" method="post"><button class="txtbtn" value="unsilence" type="submit">🔊</button></form>`))
+			}
 
 			if reason := a.allow(dashboard, alert); reason == "" {
 				alerts = append(alerts, alert)
@@ -440,11 +605,46 @@ func (a *Aggregator) notify(ctx context.Context) {
 
 func (a *Aggregator) Reconfigure(cfg *config.Config) {
 	a.cmu.Lock()
-	defer a.cmu.Unlock()
+	replaced, inflight := a.connectors, a.inflight
 
 	a.connectors = cfg.Connectors
+	a.inflight = new(sync.WaitGroup)
 	a.whereTempl = cfg.WhereTemplate
 	a.dashboards = cfg.Dashboards
+
+	// Streams are only running, once the aggregator is running
+	if a.stopStreams != nil {
+		a.stopStreams()
+		a.startStreams(a.streamCtx)
+	}
+	a.cmu.Unlock()
+
+	// Connectors holding resources (e.g. plugin processes) have to release
+	// them, as they are replaced by the newly configured ones.  Running
+	// collections and silences might still use them.
+	go func() {
+		inflight.Wait()
+		for _, c := range replaced {
+			if slices.Contains(cfg.Connectors, c) {
+				continue
+			}
+			if closer, ok := c.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					slog.Warn("error closing connector", slog.String("tag", c.Tag()), slog.Any("error", err))
+				}
+			}
+		}
+	}()
+}
+
+// acquire returns the configured connectors, which are not closed on
+// reconfiguration until the returned function is called.
+func (a *Aggregator) acquire() ([]connectors.Connector, func()) {
+	a.cmu.RLock()
+	defer a.cmu.RUnlock()
+
+	a.inflight.Add(1)
+	return a.connectors, a.inflight.Done
 }
 
 // allow will match rules against the ruleset.
@@ -518,31 +718,50 @@ func (a *Aggregator) timeSince(when time.Time) time.Duration {
 }
 
 func (a *Aggregator) Silence(ctx context.Context, alertId, user string) {
-	var alert Alert
+	_, done := a.acquire()
+	defer done()
+
+	alert := a.find(alertId)
+
+	if alert.Silence != nil {
+		if err := alert.Silence(ctx, 24*time.Hour, user); err != nil {
+			slog.InfoContext(ctx, "error silencing", slog.Any("error", err))
+		}
+	}
+}
+
+func (a *Aggregator) Unsilence(ctx context.Context, alertId, user string) {
+	_, done := a.acquire()
+	defer done()
+
+	alert := a.find(alertId)
 
+	if alert.Unsilence != nil {
+		if err := alert.Unsilence(ctx, user); err != nil {
+			slog.InfoContext(ctx, "error unsilencing", slog.Any("error", err))
+		}
+	}
+}
+
+// find returns the currently shown alert with the given id.
+func (a *Aggregator) find(alertId string) Alert {
 	a.amu.RLock()
-all:
+	defer a.amu.RUnlock()
+
 	for _, dashboard := range a.dashboards {
-		for _, a := range a.current[dashboard.Name].Alerts {
-			if a.Id == alertId {
-				alert = a
-				break all
+		for _, al := range a.current[dashboard.Name].Alerts {
+			if al.Id == alertId {
+				return al
 			}
 		}
 		for _, g := range a.current[dashboard.Name].GroupedAlerts {
-			for _, a := range g.Alerts {
-				if a.Id == alertId {
-					alert = a
-					break all
+			for _, al := range g.Alerts {
+				if al.Id == alertId {
+					return al
 				}
 			}
 		}
 	}
-	a.amu.RUnlock()
 
-	if alert.Silence != nil {
-		if err := alert.Silence(ctx, 24*time.Hour, user); err != nil {
-			slog.InfoContext(ctx, "error silencing", slog.Any("error", err))
-		}
-	}
+	return Alert{}
 }
@@ -2,6 +2,7 @@ package aggregation
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -78,6 +79,87 @@ func TestWhat(t *testing.T) {
 	}
 }
 
+func TestStreamRefresh(t *testing.T) {
+	a := aggregator(config.Excluding, false)
+	streamer := &mockStreamer{mockConnector: mockConnector{clock: clock.NewMock()}, started: make(chan func(), 1)}
+	a.connectors = append(a.connectors, streamer)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := a.Register("test")
+	go a.Run(ctx)
+
+	changed := <-streamer.started
+	select {
+	case <-update:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for collection")
+	}
+	if len(a.Alerts("Home").Alerts) != 6 {
+		t.Fatal("All connectors should be collected", a.Alerts("Home").Alerts)
+	}
+
+	streamer.mu.Lock()
+	streamer.extra = true
+	streamer.mu.Unlock()
+	changed()
+
+	select {
+	case <-update:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for refresh")
+	}
+	if len(a.Alerts("Home").Alerts) != 7 {
+		t.Error("Changes should be refreshed immediately, keeping other results", a.Alerts("Home").Alerts)
+	}
+}
+
+func TestStoreKeepsLatestResults(t *testing.T) {
+	a := aggregator(config.Excluding, false)
+	c := a.connectors[0]
+	ctx := context.Background()
+
+	a.store(ctx, []result{{tag: "mock", connector: c, generation: 2, alerts: make([]connectors.Alert, 2)}}, false)
+	a.store(ctx, []result{{tag: "mock", connector: c, generation: 1, alerts: make([]connectors.Alert, 1)}}, true)
+
+	if len(a.results) != 1 || a.results[0].generation != 2 {
+		t.Error("A slower collection should not overwrite a newer refresh", a.results)
+	}
+	if len(a.Alerts("Home").Alerts) != 2 {
+		t.Error("The newer refresh should be aggregated", a.Alerts("Home").Alerts)
+	}
+}
+
+func TestReconfigureClosesAfterCollection(t *testing.T) {
+	a := aggregator(config.Excluding, false)
+	closer := &mockCloser{mockConnector: mockConnector{clock: clock.NewMock()}, collecting: make(chan bool), release: make(chan bool), closed: make(chan bool)}
+	a.connectors = []connectors.Connector{closer}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collect := make(chan result, 1)
+	go a.collect(ctx, collect)
+	<-closer.collecting
+
+	cfg, _ := config.NewConfiguration()
+	a.Reconfigure(cfg)
+
+	select {
+	case <-closer.closed:
+		t.Fatal("Connectors should not be closed while collecting")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(closer.release)
+	select {
+	case <-closer.closed:
+	case <-ctx.Done():
+		t.Fatal("Replaced connectors should be closed after the collection")
+	}
+}
+
 func aggregator(mode config.DashboardMode, groupAlerts bool, filters ...config.Rule) *Aggregator {
 	cfg, _ := config.NewConfiguration()
 	log.Initialize(cfg)
@@ -167,3 +249,50 @@ func (m *mockConnector) Collect(_ context.Context) ([]connectors.Alert, error) {
 	}
 	return alerts, nil
 }
+
+type mockStreamer struct {
+	mockConnector
+	started chan func()
+
+	mu    sync.Mutex
+	extra bool
+}
+
+func (m *mockStreamer) Stream(ctx context.Context, changed func()) {
+	m.started <- changed
+	<-ctx.Done()
+}
+
+func (m *mockStreamer) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	alerts, err := m.mockConnector.Collect(ctx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.extra {
+		alerts = append(alerts, connectors.Alert{
+			Labels:      map[string]string{"Hostname": "icinga"},
+			Description: "Host down",
+			Start:       m.clock.Now(),
+			State:       connectors.Critical,
+		})
+	}
+	return alerts, err
+}
+
+type mockCloser struct {
+	mockConnector
+	collecting chan bool
+	release    chan bool
+	closed     chan bool
+}
+
+func (m *mockCloser) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	m.collecting <- true
+	<-m.release
+	return m.mockConnector.Collect(ctx)
+}
+
+func (m *mockCloser) Close() error {
+	close(m.closed)
+	return nil
+}
@@ -16,17 +16,37 @@ import (
 
 	"github.com/synyx/tuwat/pkg/connectors"
 	"github.com/synyx/tuwat/pkg/connectors/alertmanager"
+	"github.com/synyx/tuwat/pkg/connectors/argocd"
+	"github.com/synyx/tuwat/pkg/connectors/checkmk"
+	"github.com/synyx/tuwat/pkg/connectors/consul"
+	"github.com/synyx/tuwat/pkg/connectors/docker"
 	"github.com/synyx/tuwat/pkg/connectors/example"
+	"github.com/synyx/tuwat/pkg/connectors/exec"
+	"github.com/synyx/tuwat/pkg/connectors/genericjson"
 	"github.com/synyx/tuwat/pkg/connectors/github"
+	"github.com/synyx/tuwat/pkg/connectors/githubsecurity"
 	"github.com/synyx/tuwat/pkg/connectors/gitlabmr"
+	"github.com/synyx/tuwat/pkg/connectors/gitlabpipeline"
 	"github.com/synyx/tuwat/pkg/connectors/grafana"
 	"github.com/synyx/tuwat/pkg/connectors/graylog"
 	"github.com/synyx/tuwat/pkg/connectors/icinga2"
+	"github.com/synyx/tuwat/pkg/connectors/jira"
+	"github.com/synyx/tuwat/pkg/connectors/kubernetes"
+	"github.com/synyx/tuwat/pkg/connectors/livestatus"
 	"github.com/synyx/tuwat/pkg/connectors/nagiosapi"
+	"github.com/synyx/tuwat/pkg/connectors/opsgenie"
 	"github.com/synyx/tuwat/pkg/connectors/orderview"
+	"github.com/synyx/tuwat/pkg/connectors/pagerduty"
 	"github.com/synyx/tuwat/pkg/connectors/patchman"
+	"github.com/synyx/tuwat/pkg/connectors/plugin"
+	"github.com/synyx/tuwat/pkg/connectors/probe"
+	"github.com/synyx/tuwat/pkg/connectors/prometheus"
+	"github.com/synyx/tuwat/pkg/connectors/promql"
 	"github.com/synyx/tuwat/pkg/connectors/redmine"
+	"github.com/synyx/tuwat/pkg/connectors/sentry"
+	"github.com/synyx/tuwat/pkg/connectors/sonarqube"
 	"github.com/synyx/tuwat/pkg/connectors/wizio"
+	"github.com/synyx/tuwat/pkg/connectors/zabbix"
 )
 
 var fVersion = flag.Bool("version", false, "Print version")
@@ -83,20 +103,40 @@ type dashboardConfig struct {
 }
 
 type rootConfig struct {
-	Main          mainConfig               `toml:"main"`
-	Rules         []map[string]interface{} `toml:"rule"`
-	Alertmanagers []alertmanager.Config    `toml:"alertmanager"`
-	GitlabMRs     []gitlabmr.Config        `toml:"gitlabmr"`
-	Icinga2s      []icinga2.Config         `toml:"icinga2"`
-	NagiosAPIs    []nagiosapi.Config       `toml:"nagiosapi"`
-	Patchmans     []patchman.Config        `toml:"patchman"`
-	GitHubIssues  []github.Config          `toml:"github"`
-	Redmines      []redmine.Config         `toml:"redmine"`
-	Orderview     []orderview.Config       `toml:"orderview"`
-	Example       []example.Config         `toml:"example"`
-	Graylogs      []graylog.Config         `toml:"graylog"`
-	Wizio         []wizio.Config           `toml:"wizio"`
-	Grafanas      []grafana.Config         `toml:"grafana"`
+	Main             mainConfig               `toml:"main"`
+	Rules            []map[string]interface{} `toml:"rule"`
+	Alertmanagers    []alertmanager.Config    `toml:"alertmanager"`
+	GitlabMRs        []gitlabmr.Config        `toml:"gitlabmr"`
+	Icinga2s         []icinga2.Config         `toml:"icinga2"`
+	NagiosAPIs       []nagiosapi.Config       `toml:"nagiosapi"`
+	Patchmans        []patchman.Config        `toml:"patchman"`
+	GitHubIssues     []github.Config          `toml:"github"`
+	Redmines         []redmine.Config         `toml:"redmine"`
+	Orderview        []orderview.Config       `toml:"orderview"`
+	Example          []example.Config         `toml:"example"`
+	Graylogs         []graylog.Config         `toml:"graylog"`
+	Wizio            []wizio.Config           `toml:"wizio"`
+	Grafanas         []grafana.Config         `toml:"grafana"`
+	Plugins          []plugin.Config          `toml:"plugin"`
+	Execs            []exec.Config            `toml:"exec"`
+	JSONs            []genericjson.Config     `toml:"json"`
+	Prometheuses     []prometheus.Config      `toml:"prometheus"`
+	PromQLs          []promql.Config          `toml:"promql"`
+	Zabbixes         []zabbix.Config          `toml:"zabbix"`
+	Checkmks         []checkmk.Config         `toml:"checkmk"`
+	Sentries         []sentry.Config          `toml:"sentry"`
+	Jiras            []jira.Config            `toml:"jira"`
+	Kubernetes       []kubernetes.Config      `toml:"kubernetes"`
+	Probes           []probe.Config           `toml:"probe"`
+	GitlabPipelines  []gitlabpipeline.Config  `toml:"gitlabpipeline"`
+	GithubSecurities []githubsecurity.Config  `toml:"githubsecurity"`
+	ArgoCDs          []argocd.Config          `toml:"argocd"`
+	SonarQubes       []sonarqube.Config       `toml:"sonarqube"`
+	Consuls          []consul.Config          `toml:"consul"`
+	Dockers          []docker.Config          `toml:"docker"`
+	Opsgenies        []opsgenie.Config        `toml:"opsgenie"`
+	PagerDuties      []pagerduty.Config       `toml:"pagerduty"`
+	Livestatus       []livestatus.Config      `toml:"livestatus"`
 }
 
 func NewConfiguration() (config *Config, err error) {
@@ -258,6 +298,66 @@ func (cfg *Config) configureMain(rootConfig *rootConfig) (err error) {
 	for _, connectorConfig := range rootConfig.Grafanas {
 		cfg.Connectors = append(cfg.Connectors, grafana.NewConnector(&connectorConfig))
 	}
+	for _, connectorConfig := range rootConfig.Plugins {
+		cfg.Connectors = append(cfg.Connectors, plugin.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Execs {
+		cfg.Connectors = append(cfg.Connectors, exec.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.JSONs {
+		cfg.Connectors = append(cfg.Connectors, genericjson.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Prometheuses {
+		cfg.Connectors = append(cfg.Connectors, prometheus.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.PromQLs {
+		cfg.Connectors = append(cfg.Connectors, promql.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Zabbixes {
+		cfg.Connectors = append(cfg.Connectors, zabbix.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Checkmks {
+		cfg.Connectors = append(cfg.Connectors, checkmk.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Sentries {
+		cfg.Connectors = append(cfg.Connectors, sentry.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Jiras {
+		cfg.Connectors = append(cfg.Connectors, jira.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Kubernetes {
+		cfg.Connectors = append(cfg.Connectors, kubernetes.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Probes {
+		cfg.Connectors = append(cfg.Connectors, probe.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.GitlabPipelines {
+		cfg.Connectors = append(cfg.Connectors, gitlabpipeline.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.GithubSecurities {
+		cfg.Connectors = append(cfg.Connectors, githubsecurity.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.ArgoCDs {
+		cfg.Connectors = append(cfg.Connectors, argocd.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.SonarQubes {
+		cfg.Connectors = append(cfg.Connectors, sonarqube.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Consuls {
+		cfg.Connectors = append(cfg.Connectors, consul.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Dockers {
+		cfg.Connectors = append(cfg.Connectors, docker.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Opsgenies {
+		cfg.Connectors = append(cfg.Connectors, opsgenie.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.PagerDuties {
+		cfg.Connectors = append(cfg.Connectors, pagerduty.NewConnector(&connectorConfig))
+	}
+	for _, connectorConfig := range rootConfig.Livestatus {
+		cfg.Connectors = append(cfg.Connectors, livestatus.NewConnector(&connectorConfig))
+	}
 
 	// Add template for
 	cfg.WhereTemplate, err = template.New("where").
@@ -1,8 +1,10 @@
 package alertmanager
 
 type alert struct {
+	Fingerprint string            `json:"fingerprint"`
 	Labels      map[string]string `json:"labels"`
 	StartsAt    string            `json:"startsAt"`
+	UpdatedAt   string            `json:"updatedAt"`
 	Annotations map[string]string `json:"annotations"`
 	Status      status            `json:"status"`
 	Receivers   []receiver        `json:"receivers,omitempty"`
@@ -13,6 +15,20 @@ type status struct {
 	SilencedBy []string `json:"silencedBy"`
 }
 
+type silence struct {
+	ID        string    `json:"id"`
+	Matchers  []matcher `json:"matchers"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+}
+
+type matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
 type receiver struct {
 	Name string `json:"name"`
 }
@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	html "html/template"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/synyx/tuwat/pkg/connectors"
@@ -21,6 +25,9 @@ import (
 type Connector struct {
 	config Config
 	client *http.Client
+
+	mu    sync.Mutex      // Protecting owned
+	owned map[string]bool // whether silences by id were created via tuwat
 }
 
 type Config struct {
@@ -28,6 +35,19 @@ type Config struct {
 	Tag     string
 	Cluster string
 
+	// Peers are the URLs of all members of an HA cluster.  Alternatively, the
+	// peers are looked up via the DNS SRV record, using the scheme of the URL.
+	// The URL is still used for links, if configured.
+	Peers []string
+	SRV   string
+
+	// SilenceLabels are the labels silences match on, defaults to all labels
+	// of the alert.
+	SilenceLabels []string
+	// ShowSilenced shows silenced alerts as unknown, silences created via
+	// tuwat can be expired again.
+	ShowSilenced bool
+
 	IgnoreMissingDeadMansSwitch bool
 }
 
@@ -35,6 +55,7 @@ func NewConnector(cfg *Config) *Connector {
 	c := &Connector{
 		config: *cfg,
 		client: cfg.HTTPConfig.Client(),
+		owned:  make(map[string]bool),
 	}
 
 	return c
@@ -50,14 +71,14 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 		return nil, err
 	}
 
+	c.forgetSilences(sourceAlerts)
+
 	var alerts []connectors.Alert
 
 	if !c.config.IgnoreMissingDeadMansSwitch && len(sourceAlerts) == 0 {
-		u, _ := url.Parse(c.config.URL)
-
 		alert := connectors.Alert{
 			Labels: map[string]string{
-				"Hostname": u.Host,
+				"Hostname": c.hostname(),
 			},
 			Start:       time.Now(),
 			State:       connectors.Critical,
@@ -66,21 +87,33 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 		alerts = append(alerts, alert)
 	}
 
-	for _, sourceAlert := range sourceAlerts {
+	for _, peerAlert := range sourceAlerts {
+		sourceAlert := peerAlert.alert
+		baseURL := c.config.URL
+		if baseURL == "" {
+			baseURL = peerAlert.peer
+		}
+
 		severity := ""
 		if s, ok := sourceAlert.Labels["severity"]; ok {
 			severity = s
 		}
 
-		if sourceAlert.Status.State == stateSuppressed {
+		silenced := len(sourceAlert.Status.SilencedBy) > 0
+		if silenced && !c.config.ShowSilenced {
 			continue
-		} else if len(sourceAlert.Status.SilencedBy) > 0 {
+		} else if !silenced && sourceAlert.Status.State == stateSuppressed {
+			// Inhibited by another alert
 			continue
 		} else if severity == severityNone {
 			continue
 		}
 
+		// Silenced alerts are suppressed and thus unknown
 		state := stateFromSourceAlert(ctx, sourceAlert, severity)
+		if silenced {
+			state = connectors.Unknown
+		}
 
 		last, err := time.Parse("2006-01-02T15:04:05Z07", sourceAlert.StartsAt)
 		if err != nil {
@@ -96,9 +129,12 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 			"uid": sourceAlert.Labels["uid"],
 		}
 		if filter, err := json.Marshal(filterLabels); err == nil {
-			link := c.config.URL + "/#/alerts?filter=" + url.QueryEscape(string(filter))
+			link := baseURL + "/#/alerts?filter=" + url.QueryEscape(string(filter))
 			links = append(links, html.HTML("<a href=\""+link+"\" target=\"_blank\"  alt=\"Home\">🏠</a>"))
 		}
+		for _, id := range sourceAlert.Status.SilencedBy {
+			links = append(links, html.HTML("<a href=\""+baseURL+"/#/silences/"+url.PathEscape(id)+"\" target=\"_blank\" alt=\"Silence\">🔇</a>"))
+		}
 
 		descr := sourceAlert.Labels["alertname"]
 		details := strings.Join(k8sLabels(sourceAlert.Annotations, "summary", "description"), "\n")
@@ -112,11 +148,12 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 		tags := map[string]string{
 			"Cluster":   c.config.Cluster,
 			"Namespace": namespace,
-			"Source":    c.config.URL,
+			"Source":    baseURL,
 		}
 		for k, v := range sourceAlert.Labels {
 			tags[k] = v
 		}
+		tags["Silenced"] = strings.Join(sourceAlert.Status.SilencedBy, ",")
 
 		alert := connectors.Alert{
 			Labels:      tags,
@@ -126,7 +163,11 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 			Details:     details,
 			Links:       links,
 		}
-		alert.Silence = c.createSilencer(alert)
+		if !silenced {
+			alert.Silence = c.createSilencer(sourceAlert.Fingerprint, sourceAlert.Labels)
+		} else if ids := c.ownSilences(ctx, sourceAlert.Status.SilencedBy); len(ids) > 0 {
+			alert.Unsilence = c.createUnsilencer(sourceAlert.Fingerprint, ids)
+		}
 		alerts = append(alerts, alert)
 	}
 
@@ -155,6 +196,9 @@ func stateFromSourceAlert(ctx context.Context, sourceAlert alert, severity strin
 }
 
 func (c *Connector) String() string {
+	if c.config.URL == "" && c.config.SRV != "" {
+		return fmt.Sprintf("Alertmanager (%s)", c.config.SRV)
+	}
 	return fmt.Sprintf("Alertmanager (%s)", c.config.URL)
 }
 
@@ -168,23 +212,150 @@ func k8sLabels(haystack map[string]string, needles ...string) []string {
 	return out
 }
 
-func (c *Connector) collectAlerts(ctx context.Context) ([]alert, error) {
-	res, err := c.get(ctx, "/api/v2/alerts")
+// forgetSilences drops the known owners of silences, which do not silence
+// any alert anymore.
+func (c *Connector) forgetSilences(sourceAlerts []peerAlert) {
+	active := make(map[string]bool)
+	for _, a := range sourceAlerts {
+		for _, id := range a.Status.SilencedBy {
+			active[id] = true
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range c.owned {
+		if !active[id] {
+			delete(c.owned, id)
+		}
+	}
+}
+
+// hostname identifies the cluster by the host of the URL, or else by the SRV
+// record or the first peer.
+func (c *Connector) hostname() string {
+	base := c.config.URL
+	if base == "" && c.config.SRV != "" {
+		return c.config.SRV
+	} else if base == "" && len(c.config.Peers) > 0 {
+		base = c.config.Peers[0]
+	}
+
+	u, _ := url.Parse(base)
+	return u.Host
+}
+
+// peerAlert is an alert together with the peer it was collected from.
+type peerAlert struct {
+	alert
+	peer string
+}
+
+// peers returns the URLs of all peers of the cluster.
+func (c *Connector) peers(ctx context.Context) ([]string, error) {
+	if c.config.SRV != "" {
+		scheme := "http"
+		if u, err := url.Parse(c.config.URL); err == nil && u.Scheme != "" {
+			scheme = u.Scheme
+		}
+
+		_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", c.config.SRV)
+		if err != nil {
+			return nil, err
+		}
+
+		var peers []string
+		for _, r := range records {
+			host := strings.TrimSuffix(r.Target, ".")
+			peers = append(peers, scheme+"://"+net.JoinHostPort(host, strconv.Itoa(int(r.Port))))
+		}
+		return peers, nil
+	}
+
+	if len(c.config.Peers) > 0 {
+		return c.config.Peers, nil
+	}
+
+	return []string{c.config.URL}, nil
+}
+
+// collectAlerts queries all peers concurrently and merges their alerts by
+// fingerprint, preferring the most recently updated alert, as the state of
+// the peers converges via gossip only.  Collection only fails if all peers
+// fail.
+func (c *Connector) collectAlerts(ctx context.Context) ([]peerAlert, error) {
+	peers, err := c.peers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([][]alert, len(peers))
+	errs := make([]error, len(peers))
+
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Go(func() {
+			responses[i], errs[i] = c.collectPeerAlerts(ctx, peer)
+		})
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, err := range errs {
+		if err != nil {
+			failed++
+			slog.WarnContext(ctx, "Cannot collect from peer",
+				slog.String("peer", peers[i]),
+				slog.Any("error", err))
+		}
+	}
+	if failed == len(peers) {
+		return nil, errors.Join(errs...)
+	}
+
+	var merged []peerAlert
+	index := make(map[string]int)
+	for i, response := range responses {
+		for _, a := range response {
+			j, ok := index[a.Fingerprint]
+			if !ok || a.Fingerprint == "" {
+				index[a.Fingerprint] = len(merged)
+				merged = append(merged, peerAlert{alert: a, peer: peers[i]})
+			} else if parseTime(a.UpdatedAt).After(parseTime(merged[j].UpdatedAt)) {
+				merged[j] = peerAlert{alert: a, peer: peers[i]}
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+func (c *Connector) collectPeerAlerts(ctx context.Context, peer string) ([]alert, error) {
+	res, err := c.get(ctx, peer, "/api/v2/alerts")
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("failed to get %s, status code %d: %s", peer, res.StatusCode, string(b))
+	}
+
 	b, _ := io.ReadAll(res.Body)
 	buf := bytes.NewBuffer(b)
-
 	decoder := json.NewDecoder(buf)
 
 	var response []alert
 	err = decoder.Decode(&response)
 	if err != nil {
 		slog.ErrorContext(ctx, "Cannot parse",
-			slog.String("url", c.config.URL),
+			slog.String("url", peer),
 			slog.String("data", buf.String()),
 			slog.Any("status", res.StatusCode),
 			slog.Any("error", err))
@@ -194,11 +365,10 @@ func (c *Connector) collectAlerts(ctx context.Context) ([]alert, error) {
 	return response, nil
 }
 
-func (c *Connector) get(ctx context.Context, endpoint string) (*http.Response, error) {
-
-	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+func (c *Connector) get(ctx context.Context, peer, endpoint string) (*http.Response, error) {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", peer+endpoint))
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, peer+endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -6,8 +6,11 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"regexp"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/BurntSushi/toml"
 
@@ -44,6 +47,209 @@ func TestConnector(t *testing.T) {
 	}
 }
 
+func TestPeers(t *testing.T) {
+	var silenced atomic.Int32
+	down := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	stale := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			silenced.Add(1)
+			_, _ = res.Write([]byte(`{"silenceID": "7d8c5d7a-4b2e-4c3f-9f0a-8c0e5b1d2a3f"}`))
+			return
+		}
+		_, _ = res.Write([]byte(mockResponse))
+	}))
+	defer stale.Close()
+	current := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			silenced.Add(1)
+			_, _ = res.Write([]byte(`{"silenceID": "7d8c5d7a-4b2e-4c3f-9f0a-8c0e5b1d2a3f"}`))
+			return
+		}
+		// The peer already knows about a silence of the first alert
+		response := strings.Replace(mockResponse, `"silencedBy": [],`, `"silencedBy": ["7d8c5d7a-4b2e-4c3f-9f0a-8c0e5b1d2a3f"],`, 1)
+		response = strings.Replace(response, `"updatedAt": "2022-09-25T16:51:09.004Z"`, `"updatedAt": "2022-09-25T16:52:09.004Z"`, 1)
+		_, _ = res.Write([]byte(response))
+	}))
+	defer current.Close()
+
+	cfg := Config{
+		Tag:   "test",
+		Peers: []string{down.URL, stale.URL, current.URL},
+	}
+	connector := NewConnector(&cfg)
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal("Collection should only fail if all peers fail", err)
+	}
+	if len(alerts) != 2 {
+		t.Fatal("Alerts should be merged, preferring the most recent state", alerts)
+	}
+	if alerts[0].Labels["alertname"] != "GatekeeperConstraintViolations" || alerts[0].Labels["Source"] != stale.URL {
+		t.Error("Alerts should link to the peer they were collected from", alerts[0].Labels)
+	}
+
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+	if silenced.Load() != 1 {
+		t.Error("Silences should be sent to exactly one healthy peer")
+	}
+
+	cfg = Config{
+		Tag:   "test",
+		Peers: []string{down.URL, down.URL},
+	}
+	if _, err := NewConnector(&cfg).Collect(context.Background()); err == nil {
+		t.Error("Collection should fail if all peers fail")
+	}
+}
+
+func TestDeadMansSwitchPeers(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		_, _ = res.Write([]byte(`[]`))
+	}))
+	defer testServer.Close()
+
+	cfg := Config{
+		Tag:   "test",
+		Peers: []string{testServer.URL},
+	}
+	alerts, err := NewConnector(&cfg).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 1 || alerts[0].Description != "DeadMansSwitch dead" {
+		t.Fatal("A missing DeadMansSwitch should be an alert", alerts)
+	}
+	if alerts[0].Labels["Hostname"] != strings.TrimPrefix(testServer.URL, "http://") {
+		t.Error("The first peer should be the hostname", alerts[0].Labels)
+	}
+}
+
+func TestSilence(t *testing.T) {
+	const (
+		foreignSilence = "7d8c5d7a-4b2e-4c3f-9f0a-8c0e5b1d2a3f"
+		ownSilence     = "0b1c2d3e-1111-2222-3333-444455556666"
+	)
+	var created struct {
+		Matchers  []matcher `json:"matchers"`
+		CreatedBy string    `json:"createdBy"`
+		Comment   string    `json:"comment"`
+	}
+	var silenced atomic.Bool
+	var lookups atomic.Int32
+	var expired []string
+	testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case req.Method == http.MethodPost:
+			_ = json.NewDecoder(req.Body).Decode(&created)
+			silenced.Store(true)
+			_, _ = res.Write([]byte(`{"silenceID": "` + ownSilence + `"}`))
+		case req.Method == http.MethodDelete:
+			expired = append(expired, strings.TrimPrefix(req.URL.Path, "/api/v2/silence/"))
+		case req.URL.Path == "/api/v2/silence/"+ownSilence:
+			lookups.Add(1)
+			_ = json.NewEncoder(res).Encode(silence{ID: ownSilence, CreatedBy: "jdoe", Comment: created.Comment})
+		case req.URL.Path == "/api/v2/silence/"+foreignSilence:
+			lookups.Add(1)
+			_ = json.NewEncoder(res).Encode(silence{ID: foreignSilence, CreatedBy: "ops", Comment: "maintenance"})
+		default:
+			// The first alert is silenced by someone else, the second one via
+			// tuwat as well, once silenced
+			active := `"silencedBy": [],
+      "state": "active"`
+			response := strings.Replace(mockResponse, active, `"silencedBy": ["`+foreignSilence+`"],
+      "state": "suppressed"`, 1)
+			if silenced.Load() {
+				response = strings.Replace(response, active, `"silencedBy": ["`+foreignSilence+`", "`+ownSilence+`"],
+      "state": "suppressed"`, 1)
+			}
+			_, _ = res.Write([]byte(response))
+		}
+	}))
+	defer testServer.Close()
+
+	cfg := Config{
+		Tag:           "test",
+		SilenceLabels: []string{"alertname", "namespace", "missing"},
+		HTTPConfig: common.HTTPConfig{
+			URL:         testServer.URL,
+			BearerToken: "secret",
+		},
+	}
+	connector := NewConnector(&cfg)
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 {
+		t.Fatal("Silenced alerts should be hidden by default", alerts)
+	}
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+	if created.CreatedBy != "jdoe" || len(created.Matchers) != 2 ||
+		created.Matchers[0] != (matcher{Name: "alertname", Value: alerts[0].Labels["alertname"], IsEqual: true}) ||
+		created.Matchers[1].Name != "namespace" {
+		t.Error("Silences should match on the configured labels", created)
+	}
+
+	alerts, err = connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 {
+		t.Fatal("Alerts silenced via tuwat should be hidden by default", alerts)
+	}
+
+	// Own silences are recognized after a restart as well
+	cfg.SilenceLabels = nil
+	cfg.ShowSilenced = true
+	connector = NewConnector(&cfg)
+	for range 2 {
+		alerts, err = connector.Collect(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lookups.Load() != 2 {
+		t.Error("Silences should only be looked up once", lookups.Load())
+	}
+	if len(alerts) != 3 || alerts[0].State != connectors.Unknown || alerts[0].Unsilence != nil {
+		t.Fatal("Alerts silenced by others should be shown as unknown, but not be unsilenced", alerts)
+	}
+	if alerts[1].State != connectors.Unknown || alerts[1].Labels["Silenced"] != foreignSilence+","+ownSilence ||
+		!strings.Contains(string(alerts[1].Links[len(alerts[1].Links)-1]), "/#/silences/"+ownSilence) {
+		t.Fatal("Silenced alerts should be shown with their silences", alerts[1])
+	}
+	if alerts[1].Silence != nil || alerts[1].Unsilence == nil {
+		t.Fatal("Alerts silenced via tuwat should be unsilenced", alerts[1])
+	}
+	if err := alerts[1].Unsilence(context.Background(), "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(expired, []string{ownSilence}) {
+		t.Error("Only silences created via tuwat should be expired", expired)
+	}
+
+	if err := alerts[2].Silence(context.Background(), time.Hour, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+	if len(created.Matchers) != len(alerts[2].Labels)-4 {
+		t.Error("Silences should match on all labels of the alert", created.Matchers)
+	}
+}
+
 func TestDefaultConfig(t *testing.T) {
 	confText := `Tag = "test"`
 	conf := Config{}
@@ -4,69 +4,196 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/synyx/tuwat/pkg/connectors"
 	"github.com/synyx/tuwat/pkg/version"
 )
 
-func (c *Connector) createSilencer(alert connectors.Alert) connectors.SilencerFunc {
+func (c *Connector) createSilencer(fingerprint string, labels map[string]string) connectors.SilencerFunc {
 
 	return func(ctx context.Context, duration time.Duration, user string) error {
 
-		return c.Silence(ctx, alert, duration, user)
+		return c.Silence(ctx, fingerprint, labels, duration, user)
 	}
 }
 
-func (c *Connector) Silence(ctx context.Context, alert connectors.Alert, duration time.Duration, user string) error {
+func (c *Connector) createUnsilencer(fingerprint string, ids []string) connectors.UnsilencerFunc {
 
+	return func(ctx context.Context, user string) error {
+
+		return c.Unsilence(ctx, fingerprint, ids, user)
+	}
+}
+
+// Silence creates a silence matching the labels of the alert, or the
+// configured subset of them.  The silence is recognizable by its comment, to
+// be able to expire it again.
+//
+// see https://github.com/prometheus/alertmanager/blob/main/api/v2/openapi.yaml
+func (c *Connector) Silence(ctx context.Context, fingerprint string, labels map[string]string, duration time.Duration, user string) error {
+	ms := c.matchers(labels)
+	if len(ms) == 0 {
+		return fmt.Errorf("alertmanager: no labels to silence alert %s", fingerprint)
+	}
+
+	now := time.Now()
 	payload := map[string]interface{}{
-		"matchers": map[string]interface{}{
-			"name":    "uid",
-			"value":   alert.Labels["uid"],
-			"isRegex": false,
-		},
-		"startsAt":  time.Now().Format(time.RFC3339),
-		"endsAt":    time.Now().Add(duration).Format(time.RFC3339),
+		"matchers":  ms,
+		"startsAt":  now.Format(time.RFC3339),
+		"endsAt":    now.Add(duration).Format(time.RFC3339),
 		"createdBy": user,
-		"comment":   fmt.Sprintf("%s: silenced via %s", user, version.Info.Application),
+		"comment":   fmt.Sprintf("%s: %s", user, silenceComment()),
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return err
 	}
-	endpoint := "/api/v2/silences"
 
-	switch alert.Labels["type"] {
-	case "Service":
-		payload["filter"] = `host.name=="` + alert.Labels["Hostname"] + `" && service.name=="` + alert.Details + `"`
-	case "Host":
-		payload["filter"] = `host.name=="` + alert.Labels["Hostname"] + `"`
+	var response struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := c.send(ctx, http.MethodPost, "/api/v2/silences", b, &response); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "silenced alert",
+		slog.String("fingerprint", fingerprint),
+		slog.String("silence", response.SilenceID),
+		slog.String("user", user))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.owned[response.SilenceID] = true
+
+	return nil
+}
+
+// silenceComment marks silences created via tuwat.
+func silenceComment() string {
+	return "silenced via " + version.Info.Application
+}
+
+// Unsilence expires the given silences of the alert, which have to be
+// created via Silence, see ownSilences.  Silences created by others, e.g. for a maintenance,
+// might cover further alerts and are never expired.
+func (c *Connector) Unsilence(ctx context.Context, fingerprint string, ids []string, user string) error {
+	for _, id := range ids {
+		if err := c.send(ctx, http.MethodDelete, "/api/v2/silence/"+url.PathEscape(id), nil, nil); err != nil {
+			return err
+		}
+
+		slog.InfoContext(ctx, "expired silence",
+			slog.String("fingerprint", fingerprint),
+			slog.String("silence", id),
+			slog.String("user", user))
 	}
 
-	err := c.post(ctx, endpoint, payload)
+	return nil
+}
 
-	return err
+// ownSilences returns the silences, which were created via Silence.  The
+// silences are looked up once, as they survive restarts of tuwat.
+func (c *Connector) ownSilences(ctx context.Context, silencedBy []string) []string {
+	var ids []string
+	for _, id := range silencedBy {
+		c.mu.Lock()
+		owned, known := c.owned[id]
+		c.mu.Unlock()
+
+		if !known {
+			var s silence
+			if err := c.send(ctx, http.MethodGet, "/api/v2/silence/"+url.PathEscape(id), nil, &s); err != nil {
+				slog.WarnContext(ctx, "Cannot get silence",
+					slog.String("silence", id),
+					slog.Any("error", err))
+				continue
+			}
+
+			owned = strings.HasSuffix(s.Comment, silenceComment())
+			c.mu.Lock()
+			c.owned[id] = owned
+			c.mu.Unlock()
+		}
+
+		if owned {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
-func (c *Connector) post(ctx context.Context, endpoint string, content map[string]interface{}) error {
-	buf := new(bytes.Buffer)
-	encoder := json.NewEncoder(buf)
+// matchers selects the alert by all its labels, or only by the configured
+// labels.
+func (c *Connector) matchers(labels map[string]string) []matcher {
+	var ms []matcher
+	for name, value := range labels {
+		if len(c.config.SilenceLabels) > 0 && !slices.Contains(c.config.SilenceLabels, name) {
+			continue
+		}
+		ms = append(ms, matcher{Name: name, Value: value, IsRegex: false, IsEqual: true})
+	}
+	slices.SortFunc(ms, func(a, b matcher) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return ms
+}
 
-	if err := encoder.Encode(content); err != nil {
+// send sends the content to the first healthy peer, silences are shared
+// between the peers via gossip.  The response is decoded into v, if given.
+func (c *Connector) send(ctx context.Context, method, endpoint string, b []byte, v any) error {
+	peers, err := c.peers(ctx)
+	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL+endpoint, buf)
+	var errs []error
+	for _, peer := range peers {
+		err := c.sendPeer(ctx, peer, method, endpoint, b, v)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *Connector) sendPeer(ctx context.Context, peer, method, endpoint string, b []byte, v any) error {
+	req, err := http.NewRequestWithContext(ctx, method, peer+endpoint, bytes.NewReader(b))
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if b != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 
-	return nil
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to send to %s, status code %d: %s", peer, res.StatusCode, string(body))
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
 }
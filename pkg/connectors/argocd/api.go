@@ -0,0 +1,47 @@
+package argocd
+
+// https://argo-cd.readthedocs.io/en/stable/developer-guide/api-docs/
+
+type applicationList struct {
+	Items []application `json:"items"`
+}
+
+type application struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+	Spec struct {
+		Project     string `json:"project"`
+		Destination struct {
+			Server    string `json:"server"`
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"destination"`
+	} `json:"spec"`
+	Status struct {
+		Health struct {
+			Status             string `json:"status"`
+			Message            string `json:"message"`
+			LastTransitionTime string `json:"lastTransitionTime"`
+		} `json:"health"`
+		Sync struct {
+			Status   string `json:"status"`
+			Revision string `json:"revision"`
+		} `json:"sync"`
+		OperationState *struct {
+			Phase      string `json:"phase"`
+			Message    string `json:"message"`
+			StartedAt  string `json:"startedAt"`
+			FinishedAt string `json:"finishedAt"`
+		} `json:"operationState"`
+		ReconciledAt string `json:"reconciledAt"`
+	} `json:"status"`
+}
+
+const (
+	healthDegraded    = "Degraded"
+	healthMissing     = "Missing"
+	healthProgressing = "Progressing"
+	syncOutOfSync     = "OutOfSync"
+)
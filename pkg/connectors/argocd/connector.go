@@ -0,0 +1,238 @@
+package argocd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+
+	mu          sync.Mutex // Protecting progressing and silenced
+	progressing map[string]time.Time
+	silenced    map[string]time.Time
+}
+
+type Config struct {
+	Tag string
+	// Projects and Selector restrict the applications, by Argo CD project
+	// and by label selector.
+	Projects []string
+	Selector string
+	// ProgressingTimeout is how long an application may be progressing,
+	// before it is reported.
+	ProgressingTimeout time.Duration
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.ProgressingTimeout == 0 {
+		cfg.ProgressingTimeout = 10 * time.Minute
+	}
+
+	return &Connector{
+		config:      *cfg,
+		client:      cfg.HTTPConfig.Client(),
+		progressing: make(map[string]time.Time),
+		silenced:    make(map[string]time.Time),
+	}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	apps, err := c.collectApplications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	progressing := make(map[string]time.Time)
+	var alerts []connectors.Alert
+	for _, app := range apps {
+		key := app.Metadata.Namespace + "/" + app.Metadata.Name
+		health, syncStatus := app.Status.Health.Status, app.Status.Sync.Status
+
+		since := parseTime(ctx, app.Status.Health.LastTransitionTime)
+		if health == healthProgressing {
+			// Older Argo CD versions do not report the transition time
+			if since.IsZero() {
+				since = now
+				if first, ok := c.progressing[key]; ok {
+					since = first
+				}
+			}
+			progressing[key] = since
+		}
+
+		var problems []string
+		state := connectors.OK
+		switch {
+		case health == healthDegraded:
+			problems, state = append(problems, health), connectors.Critical
+		case health == healthMissing:
+			problems, state = append(problems, health), connectors.Warning
+		case health == healthProgressing && now.Sub(since) > c.config.ProgressingTimeout:
+			problems, state = append(problems, health), connectors.Warning
+		}
+		if syncStatus == syncOutOfSync {
+			problems = append(problems, syncStatus)
+			if state == connectors.OK {
+				state = connectors.Warning
+			}
+		}
+		if state == connectors.OK {
+			continue
+		}
+
+		if until, ok := c.silenced[key]; ok && now.Before(until) {
+			continue
+		}
+
+		start := since
+		if start.IsZero() {
+			start = parseTime(ctx, app.Status.ReconciledAt)
+		}
+
+		cluster := app.Spec.Destination.Server
+		if cluster == "" {
+			cluster = app.Spec.Destination.Name
+		}
+
+		details := app.Status.Health.Message
+		if op := app.Status.OperationState; op != nil && op.Message != "" {
+			details = strings.TrimSpace(details + "\n" + op.Phase + ": " + op.Message)
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Cluster":     cluster,
+				"Namespace":   app.Spec.Destination.Namespace,
+				"Project":     app.Spec.Project,
+				"Application": app.Metadata.Name,
+				"Health":      health,
+				"Sync":        syncStatus,
+				"Source":      c.config.URL,
+				"Type":        "Application",
+			},
+			Start:       start,
+			State:       state,
+			Description: fmt.Sprintf("%s %s", app.Metadata.Name, strings.Join(problems, ", ")),
+			Details:     details,
+			Links: []html.HTML{
+				html.HTML("<a href=\"" + c.applicationURL(app) + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			},
+			Silence: c.createSilencer(key),
+		}
+		alerts = append(alerts, alert)
+	}
+	c.progressing = progressing
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Argo CD (%s)", c.config.URL)
+}
+
+// createSilencer hides the application within tuwat, Argo CD itself is not
+// changed.  Silences are lost on restart.
+func (c *Connector) createSilencer(key string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+		slog.InfoContext(ctx, "silencing application", slog.String("application", key), slog.String("user", user))
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		now := time.Now()
+		for k, until := range c.silenced {
+			if now.After(until) {
+				delete(c.silenced, k)
+			}
+		}
+		c.silenced[key] = now.Add(duration)
+
+		return nil
+	}
+}
+
+func (c *Connector) applicationURL(app application) string {
+	if app.Metadata.Namespace == "" {
+		return c.config.URL + "/applications/" + url.PathEscape(app.Metadata.Name)
+	}
+	return c.config.URL + "/applications/" + url.PathEscape(app.Metadata.Namespace) + "/" + url.PathEscape(app.Metadata.Name)
+}
+
+func (c *Connector) collectApplications(ctx context.Context) ([]application, error) {
+	endpoint := "/api/v1/applications"
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	q := req.URL.Query()
+	for _, p := range c.config.Projects {
+		q.Add("projects", p)
+	}
+	if c.config.Selector != "" {
+		q.Set("selector", c.config.Selector)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("failed to get applications, status code %d: %s", res.StatusCode, string(b))
+	}
+
+	var list applicationList
+	if err = json.NewDecoder(res.Body).Decode(&list); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+endpoint),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+func parseTime(ctx context.Context, s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse", slog.String("time", s), slog.Any("error", err))
+	}
+	return t
+}
@@ -0,0 +1,139 @@
+package argocd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	connector, mockServer := testConnector(t)
+	defer func() { mockServer.Close() }()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 3 {
+		t.Fatal("There should be alerts for degraded, out of sync and long progressing applications", alerts)
+	}
+
+	if alerts[0].Labels["Application"] != "shop" || alerts[0].State != connectors.Critical || alerts[0].Description != "shop Degraded, OutOfSync" {
+		t.Error("Degraded applications should be critical", alerts[0])
+	}
+	if alerts[0].Labels["Cluster"] != "https://prod.example.com:6443" || alerts[0].Labels["Namespace"] != "shop" {
+		t.Error("The destination should be the cluster and namespace", alerts[0].Labels)
+	}
+	if string(alerts[0].Links[0]) != `<a href="`+mockServer.URL+`/applications/argocd/shop" target="_blank" alt="Home">🏠</a>` {
+		t.Error("Alerts should link to the Argo CD UI", alerts[0].Links)
+	}
+	if alerts[1].Labels["Application"] != "docs" || alerts[1].State != connectors.Warning || alerts[1].Labels["Cluster"] != "in-cluster" {
+		t.Error("Out of sync applications should be warnings", alerts[1])
+	}
+	if alerts[2].Labels["Application"] != "payment" || alerts[2].State != connectors.Warning {
+		t.Error("Applications progressing for too long should be warnings", alerts[2])
+	}
+}
+
+func TestSilence(t *testing.T) {
+	connector, mockServer := testConnector(t)
+	defer func() { mockServer.Close() }()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jo"); err != nil {
+		t.Fatal(err)
+	}
+
+	silenced, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(silenced) != 2 || silenced[0].Labels["Application"] == "shop" {
+		t.Error("Silenced applications should be hidden", silenced)
+	}
+}
+
+func testConnector(t *testing.T) (*Connector, *httptest.Server) {
+	progressingSince := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	progressingNow := time.Now().UTC().Format(time.RFC3339)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/api/v1/applications" || req.URL.Query()["projects"][1] != "docs" {
+			t.Error("Unexpected request", req.URL)
+		}
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(fmt.Sprintf(mockApplications, progressingSince, progressingNow)))
+	}))
+
+	cfg := Config{
+		Tag:      "test",
+		Projects: []string{"default", "docs"},
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+
+	return NewConnector(&cfg), mockServer
+}
+
+const mockApplications = `
+{
+  "metadata": {},
+  "items": [
+    {
+      "metadata": {"name": "shop", "namespace": "argocd"},
+      "spec": {"project": "default", "destination": {"server": "https://prod.example.com:6443", "namespace": "shop"}},
+      "status": {
+        "health": {"status": "Degraded", "message": "Deployment \"api\" exceeded its progress deadline", "lastTransitionTime": "2024-05-02T10:11:12Z"},
+        "sync": {"status": "OutOfSync", "revision": "4f1c2d"},
+        "operationState": {"phase": "Failed", "message": "one or more objects failed to apply", "finishedAt": "2024-05-02T10:10:00Z"},
+        "reconciledAt": "2024-05-02T10:12:00Z"
+      }
+    },
+    {
+      "metadata": {"name": "docs", "namespace": "argocd"},
+      "spec": {"project": "docs", "destination": {"name": "in-cluster", "namespace": "docs"}},
+      "status": {
+        "health": {"status": "Healthy"},
+        "sync": {"status": "OutOfSync", "revision": "9a8b7c"},
+        "reconciledAt": "2024-05-02T10:12:00Z"
+      }
+    },
+    {
+      "metadata": {"name": "payment", "namespace": "argocd"},
+      "spec": {"project": "default", "destination": {"server": "https://prod.example.com:6443", "namespace": "payment"}},
+      "status": {
+        "health": {"status": "Progressing", "lastTransitionTime": "%s"},
+        "sync": {"status": "Synced"}
+      }
+    },
+    {
+      "metadata": {"name": "search", "namespace": "argocd"},
+      "spec": {"project": "default", "destination": {"server": "https://prod.example.com:6443", "namespace": "search"}},
+      "status": {
+        "health": {"status": "Progressing", "lastTransitionTime": "%s"},
+        "sync": {"status": "Synced"}
+      }
+    },
+    {
+      "metadata": {"name": "auth", "namespace": "argocd"},
+      "spec": {"project": "default", "destination": {"server": "https://prod.example.com:6443", "namespace": "auth"}},
+      "status": {
+        "health": {"status": "Healthy"},
+        "sync": {"status": "Synced"}
+      }
+    }
+  ]
+}
+`
@@ -0,0 +1,60 @@
+package checkmk
+
+// https://docs.checkmk.com/latest/en/rest_api.html
+
+type collection[T any] struct {
+	Value []object[T] `json:"value"`
+}
+
+type object[T any] struct {
+	ID         string `json:"id"`
+	Extensions T      `json:"extensions"`
+}
+
+type host struct {
+	Name                   string   `json:"name"`
+	State                  int      `json:"state"`
+	Acknowledged           int      `json:"acknowledged"`
+	ScheduledDowntimeDepth int      `json:"scheduled_downtime_depth"`
+	NotificationsEnabled   int      `json:"notifications_enabled"`
+	PluginOutput           string   `json:"plugin_output"`
+	LastStateChange        int64    `json:"last_state_change"`
+	Groups                 []string `json:"groups"`
+	NotesURL               string   `json:"notes_url"`
+}
+
+var hostColumns = []string{
+	"name", "state", "acknowledged", "scheduled_downtime_depth", "notifications_enabled",
+	"plugin_output", "last_state_change", "groups", "notes_url",
+}
+
+type service struct {
+	HostName                   string   `json:"host_name"`
+	Description                string   `json:"description"`
+	State                      int      `json:"state"`
+	Acknowledged               int      `json:"acknowledged"`
+	ScheduledDowntimeDepth     int      `json:"scheduled_downtime_depth"`
+	HostScheduledDowntimeDepth int      `json:"host_scheduled_downtime_depth"`
+	NotificationsEnabled       int      `json:"notifications_enabled"`
+	PluginOutput               string   `json:"plugin_output"`
+	LongPluginOutput           string   `json:"long_plugin_output"`
+	LastStateChange            int64    `json:"last_state_change"`
+	Groups                     []string `json:"groups"`
+	HostGroups                 []string `json:"host_groups"`
+	NotesURL                   string   `json:"notes_url"`
+}
+
+var serviceColumns = []string{
+	"host_name", "description", "state", "acknowledged", "scheduled_downtime_depth",
+	"host_scheduled_downtime_depth", "notifications_enabled", "plugin_output", "long_plugin_output",
+	"last_state_change", "groups", "host_groups", "notes_url",
+}
+
+// notOK is a livestatus query expression, selecting all objects with problems.
+const notOK = `{"op": "!=", "left": "state", "right": "0"}`
+
+const (
+	hostStateUp          = 0
+	hostStateDown        = 1
+	hostStateUnreachable = 2
+)
@@ -0,0 +1,242 @@
+/*
+Package checkmk collects host and service problems via the Checkmk REST API.
+
+The URL has to point to the API of a site, e.g.
+`https://checkmk.example.com/mysite/check_mk/api/1.0`.  Authentication of an
+automation user uses `BearerToken = "automation-user secret"`.
+*/
+package checkmk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	Tag          string
+	DashboardURL string
+	// SilenceMode is either `downtime` (default), which expires with the
+	// silence, or `acknowledge`.
+	SilenceMode string
+	common.HTTPConfig
+}
+
+const (
+	silenceModeDowntime    = "downtime"
+	silenceModeAcknowledge = "acknowledge"
+)
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.SilenceMode == "" {
+		cfg.SilenceMode = silenceModeDowntime
+	}
+
+	return &Connector{*cfg, cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	hosts, err := c.collectHosts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	services, err := c.collectServices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	ignoredHosts := make(map[string]bool)
+
+	for _, host := range hosts {
+		// Services of hosts with problems are not interesting, as the
+		// host problem has to be solved first.
+		ignoredHosts[host.Name] = true
+
+		if host.Acknowledged > 0 {
+			continue
+		} else if host.NotificationsEnabled == 0 {
+			continue
+		} else if host.ScheduledDowntimeDepth > 0 {
+			continue
+		} else if host.State == hostStateUp {
+			continue
+		}
+
+		var links []html.HTML
+		if host.NotesURL != "" {
+			links = append(links, html.HTML("<a href=\""+host.NotesURL+"\" target=\"_blank\" alt=\"Runbook\">📖</a>"))
+		}
+		links = append(links, html.HTML("<a href=\""+c.viewURL("host", host.Name, "")+"\" target=\"_blank\" alt=\"Home\">🏠</a>"))
+
+		description := "Host down"
+		if host.State == hostStateUnreachable {
+			description = "Host unreachable"
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Hostname": host.Name,
+				"Source":   c.config.URL,
+				"groups":   strings.Join(host.Groups, ","),
+				"Type":     "Host",
+			},
+			Start:       time.Unix(host.LastStateChange, 0),
+			State:       connectors.Critical,
+			Description: description,
+			Details:     host.PluginOutput,
+			Links:       links,
+		}
+		alert.Silence = c.createSilencer(host.Name, "")
+		alerts = append(alerts, alert)
+	}
+
+	for _, service := range services {
+		if ignore, ok := ignoredHosts[service.HostName]; ok && ignore {
+			continue
+		} else if service.Acknowledged > 0 {
+			continue
+		} else if service.NotificationsEnabled == 0 {
+			continue
+		} else if service.ScheduledDowntimeDepth > 0 || service.HostScheduledDowntimeDepth > 0 {
+			continue
+		} else if service.State == 0 {
+			continue
+		}
+
+		var links []html.HTML
+		if service.NotesURL != "" {
+			links = append(links, html.HTML("<a href=\""+service.NotesURL+"\" target=\"_blank\" alt=\"Runbook\">📖</a>"))
+		}
+		links = append(links, html.HTML("<a href=\""+c.viewURL("service", service.HostName, service.Description)+"\" target=\"_blank\" alt=\"Home\">🏠</a>"))
+
+		details := service.PluginOutput
+		if service.LongPluginOutput != "" {
+			details += "\n" + service.LongPluginOutput
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Hostname":   service.HostName,
+				"Source":     c.config.URL,
+				"groups":     strings.Join(service.Groups, ","),
+				"hostgroups": strings.Join(service.HostGroups, ","),
+				"Type":       "Service",
+			},
+			Start:       time.Unix(service.LastStateChange, 0),
+			State:       connectors.State(service.State),
+			Description: service.Description,
+			Details:     details,
+			Links:       links,
+		}
+		alert.Silence = c.createSilencer(service.HostName, service.Description)
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Checkmk (%s)", c.config.URL)
+}
+
+// viewURL links to the host or service view of the Checkmk GUI.
+func (c *Connector) viewURL(view, host, service string) string {
+	q := url.Values{}
+	q.Set("view_name", view)
+	q.Set("host", host)
+	if service != "" {
+		q.Set("service", service)
+	}
+	return html.HTMLEscapeString(c.config.DashboardURL + "/check_mk/view.py?" + q.Encode())
+}
+
+func (c *Connector) collectHosts(ctx context.Context) ([]host, error) {
+	var response collection[host]
+	if err := c.get(ctx, "/domain-types/host/collections/all", hostColumns, &response); err != nil {
+		return nil, err
+	}
+
+	var hosts []host
+	for _, o := range response.Value {
+		hosts = append(hosts, o.Extensions)
+	}
+	return hosts, nil
+}
+
+func (c *Connector) collectServices(ctx context.Context) ([]service, error) {
+	var response collection[service]
+	if err := c.get(ctx, "/domain-types/service/collections/all", serviceColumns, &response); err != nil {
+		return nil, err
+	}
+
+	var services []service
+	for _, o := range response.Value {
+		services = append(services, o.Extensions)
+	}
+	return services, nil
+}
+
+func (c *Connector) get(ctx context.Context, endpoint string, columns []string, v any) error {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	q := req.URL.Query()
+	q.Set("query", notOK)
+	for _, column := range columns {
+		q.Add("columns", column)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, _ := io.ReadAll(res.Body)
+	buf := bytes.NewBuffer(b)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, buf.String())
+	}
+
+	decoder := json.NewDecoder(buf)
+	err = decoder.Decode(v)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL),
+			slog.String("data", buf.String()),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package checkmk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	connector, _, mockServer := testConnector(mockHosts, mockServices)
+	defer func() { mockServer.Close() }()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts", alerts)
+	}
+
+	if alerts[0].Description != "Host down" || alerts[0].State != connectors.Critical {
+		t.Error("Host should be down", alerts[0])
+	}
+	if alerts[1].Description != "Filesystem /var" || alerts[1].State != connectors.Warning || alerts[1].Labels["Hostname"] != "web1" {
+		t.Error("Service should be a warning", alerts[1])
+	}
+	for _, alert := range alerts {
+		if alert.Labels["Hostname"] == "db1" && alert.Labels["Type"] == "Service" {
+			t.Error("Services of down hosts should be suppressed")
+		}
+	}
+}
+
+func TestAcknowledgedHostSuppressesServices(t *testing.T) {
+	hosts := strings.ReplaceAll(mockHosts, `"acknowledged": 0`, `"acknowledged": 1`)
+	connector, _, mockServer := testConnector(hosts, mockServices)
+	defer func() { mockServer.Close() }()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 1 || alerts[0].Labels["Hostname"] != "web1" {
+		t.Error("Acknowledged host should be skipped, including its services", alerts)
+	}
+}
+
+func TestSilence(t *testing.T) {
+	connector, requests, mockServer := testConnector(mockHosts, mockServices)
+	defer func() { mockServer.Close() }()
+
+	if err := connector.Silence(context.Background(), "web1", "Filesystem /var", time.Hour, "jo"); err != nil {
+		t.Fatal(err)
+	}
+	downtime := (*requests)["/domain-types/downtime/collections/service"]
+	if downtime["host_name"] != "web1" || downtime["service_descriptions"].([]any)[0] != "Filesystem /var" {
+		t.Error("Silencing should schedule a service downtime", downtime)
+	}
+
+	connector.config.SilenceMode = silenceModeAcknowledge
+	if err := connector.Silence(context.Background(), "db1", "", time.Hour, "jo"); err != nil {
+		t.Fatal(err)
+	}
+	ack := (*requests)["/domain-types/acknowledge/collections/host"]
+	if ack["host_name"] != "db1" || ack["acknowledge_type"] != "host" {
+		t.Error("Silencing should acknowledge the host", ack)
+	}
+}
+
+func testConnector(hostJson, serviceJson string) (*Connector, *map[string]map[string]any, *httptest.Server) {
+	requests := make(map[string]map[string]any)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodPost {
+			var payload map[string]any
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			requests[req.URL.Path] = payload
+			res.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		if strings.Contains(req.URL.Path, "/host/") {
+			_, _ = res.Write([]byte(hostJson))
+		} else if strings.Contains(req.URL.Path, "/service/") {
+			_, _ = res.Write([]byte(serviceJson))
+		}
+	}))
+
+	cfg := Config{
+		Tag:          "test",
+		DashboardURL: "https://checkmk.example.com/mysite",
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+
+	return NewConnector(&cfg), &requests, mockServer
+}
+
+const mockHosts = `
+{
+  "links": [],
+  "id": "host",
+  "domainType": "host",
+  "value": [
+    {
+      "links": [],
+      "domainType": "dict",
+      "id": "db1",
+      "title": "db1",
+      "members": {},
+      "extensions": {
+        "name": "db1",
+        "state": 1,
+        "acknowledged": 0,
+        "scheduled_downtime_depth": 0,
+        "notifications_enabled": 1,
+        "plugin_output": "CRIT - 10.0.0.12: rta nan, lost 100%",
+        "last_state_change": 1714644672,
+        "groups": ["databases"],
+        "notes_url": ""
+      }
+    }
+  ]
+}
+`
+
+const mockServices = `
+{
+  "links": [],
+  "id": "service",
+  "domainType": "service",
+  "value": [
+    {
+      "id": "db1-Check_MK",
+      "extensions": {
+        "host_name": "db1",
+        "description": "Check_MK",
+        "state": 2,
+        "acknowledged": 0,
+        "scheduled_downtime_depth": 0,
+        "host_scheduled_downtime_depth": 0,
+        "notifications_enabled": 1,
+        "plugin_output": "[agent] Communication failed",
+        "long_plugin_output": "",
+        "last_state_change": 1714644672,
+        "groups": [],
+        "host_groups": ["databases"],
+        "notes_url": ""
+      }
+    },
+    {
+      "id": "web1-Filesystem /var",
+      "extensions": {
+        "host_name": "web1",
+        "description": "Filesystem /var",
+        "state": 1,
+        "acknowledged": 0,
+        "scheduled_downtime_depth": 0,
+        "host_scheduled_downtime_depth": 0,
+        "notifications_enabled": 1,
+        "plugin_output": "Used: 85.00% - 17.0 GiB of 20.0 GiB (warn/crit at 80.00%/90.00% used)",
+        "long_plugin_output": "",
+        "last_state_change": 1714640000,
+        "groups": ["filesystems"],
+        "host_groups": ["web"],
+        "notes_url": "https://wiki.example.com/disk"
+      }
+    },
+    {
+      "id": "web2-Filesystem /",
+      "extensions": {
+        "host_name": "web2",
+        "description": "Filesystem /",
+        "state": 2,
+        "acknowledged": 0,
+        "scheduled_downtime_depth": 0,
+        "host_scheduled_downtime_depth": 1,
+        "notifications_enabled": 1,
+        "plugin_output": "Used: 95.00%",
+        "long_plugin_output": "",
+        "last_state_change": 1714640000,
+        "groups": [],
+        "host_groups": ["web"],
+        "notes_url": ""
+      }
+    }
+  ]
+}
+`
@@ -0,0 +1,96 @@
+package checkmk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/version"
+)
+
+func (c *Connector) createSilencer(host, service string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+
+		return c.Silence(ctx, host, service, duration, user)
+	}
+}
+
+// Silence schedules a downtime or acknowledges the problem, depending on the
+// configured SilenceMode.  An empty service silences the host.
+func (c *Connector) Silence(ctx context.Context, host, service string, duration time.Duration, user string) error {
+	comment := fmt.Sprintf("%s: silenced via %s", user, version.Info.Application)
+
+	typ := "host"
+	if service != "" {
+		typ = "service"
+	}
+
+	var endpoint string
+	var payload map[string]interface{}
+	switch c.config.SilenceMode {
+	case silenceModeAcknowledge:
+		endpoint = "/domain-types/acknowledge/collections/" + typ
+		payload = map[string]interface{}{
+			"acknowledge_type": typ,
+			"host_name":        host,
+			"sticky":           true,
+			"persistent":       false,
+			"notify":           false,
+			"comment":          comment,
+		}
+		if service != "" {
+			payload["service_description"] = service
+		}
+	default:
+		now := time.Now()
+		endpoint = "/domain-types/downtime/collections/" + typ
+		payload = map[string]interface{}{
+			"downtime_type": typ,
+			"host_name":     host,
+			"start_time":    now.Format(time.RFC3339),
+			"end_time":      now.Add(duration).Format(time.RFC3339),
+			"comment":       comment,
+		}
+		if service != "" {
+			payload["service_descriptions"] = []string{service}
+		}
+	}
+
+	return c.post(ctx, endpoint, payload)
+}
+
+func (c *Connector) post(ctx context.Context, endpoint string, content map[string]interface{}) error {
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+
+	if err := encoder.Encode(content); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL+endpoint, buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to silence, status code %d: %s", res.StatusCode, string(b))
+	}
+
+	return nil
+}
@@ -7,6 +7,9 @@ import (
 type HTTPConfig struct {
 	URL string
 
+	// Socket is a unix socket to connect to instead of the host of the URL
+	Socket string
+
 	// SSL
 	Insecure bool
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
@@ -15,10 +16,25 @@ import (
 
 // Client prepares a http client for a given configuration.
 func (cfg *HTTPConfig) Client() *http.Client {
+	return cfg.TLSClient(&tls.Config{InsecureSkipVerify: cfg.Insecure})
+}
+
+// TLSClient prepares a http client for a given configuration, using the given
+// TLS configuration, e.g. for custom CAs or client certificates.
+func (cfg *HTTPConfig) TLSClient(tlsConfig *tls.Config) *http.Client {
 
 	// Create transport per connector, to only configure insecure SSL where needed.
 	var tr http.RoundTripper = http.DefaultTransport.(*http.Transport).Clone()
-	tr.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: cfg.Insecure}
+	tr.(*http.Transport).TLSClientConfig = tlsConfig
+
+	// Connect via unix socket if configured, the URL is then only used for the path
+	if cfg.Socket != "" {
+		socket := cfg.Socket
+		dialer := &net.Dialer{}
+		tr.(*http.Transport).DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		}
+	}
 
 	tr = &userAgentRoundTripper{rt: tr}
 
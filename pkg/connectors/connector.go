@@ -16,8 +16,19 @@ type Collector interface {
 	String() string
 }
 
+// Streamer is implemented by connectors getting changes pushed by their
+// source, e.g. via an event stream.  Stream runs until the context is done and
+// calls changed whenever Collect would return something different.
+type Streamer interface {
+	Stream(ctx context.Context, changed func())
+}
+
 type SilencerFunc func(ctx context.Context, duration time.Duration, user string) error
 
+// UnsilencerFunc lifts a silence set before, e.g. for alerts shown although
+// being silenced.
+type UnsilencerFunc func(ctx context.Context, user string) error
+
 type Alert struct {
 	Labels      map[string]string
 	Start       time.Time
@@ -26,6 +37,7 @@ type Alert struct {
 	Details     string
 	Links       []html.HTML
 	Silence     SilencerFunc
+	Unsilence   UnsilencerFunc
 }
 
 type State int
@@ -0,0 +1,66 @@
+package consul
+
+// https://developer.hashicorp.com/consul/api-docs/health#list-checks-in-state
+
+type healthCheck struct {
+	Node        string   `json:"Node"`
+	CheckID     string   `json:"CheckID"`
+	Name        string   `json:"Name"`
+	Status      status   `json:"Status"`
+	Notes       string   `json:"Notes"`
+	Output      string   `json:"Output"`
+	ServiceID   string   `json:"ServiceID"`
+	ServiceName string   `json:"ServiceName"`
+	ServiceTags []string `json:"ServiceTags"`
+	Type        string   `json:"Type"`
+}
+
+type status = string
+
+const (
+	statusPassing  status = "passing"
+	statusWarning  status = "warning"
+	statusCritical status = "critical"
+)
+
+// https://developer.hashicorp.com/consul/api-docs/agent#read-configuration
+
+type agentSelf struct {
+	Config struct {
+		Datacenter string `json:"Datacenter"`
+	} `json:"Config"`
+}
+
+// https://developer.hashicorp.com/nomad/api-docs/allocations#list-allocations
+
+type allocation struct {
+	ID             string `json:"ID"`
+	Name           string `json:"Name"`
+	Namespace      string `json:"Namespace"`
+	JobID          string `json:"JobID"`
+	TaskGroup      string `json:"TaskGroup"`
+	NodeName       string `json:"NodeName"`
+	ClientStatus   string `json:"ClientStatus"`
+	ClientDesc     string `json:"ClientDescription"`
+	DesiredStatus  string `json:"DesiredStatus"`
+	NextAllocation string `json:"NextAllocation"`
+	// CreateTime is in nanoseconds since the epoch
+	CreateTime int64 `json:"CreateTime"`
+	ModifyTime int64 `json:"ModifyTime"`
+}
+
+// https://developer.hashicorp.com/nomad/api-docs/jobs#list-jobs
+
+type job struct {
+	ID                string `json:"ID"`
+	Name              string `json:"Name"`
+	Namespace         string `json:"Namespace"`
+	Type              string `json:"Type"`
+	Status            string `json:"Status"`
+	StatusDescription string `json:"StatusDescription"`
+	Stop              bool   `json:"Stop"`
+	Periodic          bool   `json:"Periodic"`
+	ParameterizedJob  bool   `json:"ParameterizedJob"`
+	ParentID          string `json:"ParentID"`
+	SubmitTime        int64  `json:"SubmitTime"`
+}
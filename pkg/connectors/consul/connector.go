@@ -0,0 +1,188 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config      Config
+	client      *http.Client
+	nomadClient *http.Client
+
+	mu        sync.Mutex // Protecting firstSeen
+	firstSeen map[string]time.Time
+}
+
+type Config struct {
+	Tag string
+	// Datacenters to query, defaults to the datacenter of the agent.
+	Datacenters []string
+	// Nomad is optional, allocations and jobs are only checked with a URL.
+	Nomad NomadConfig
+	common.HTTPConfig
+}
+
+type NomadConfig struct {
+	// PendingTimeout is how long an allocation may be pending, before it is
+	// reported.
+	PendingTimeout time.Duration
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.Nomad.PendingTimeout == 0 {
+		cfg.Nomad.PendingTimeout = 15 * time.Minute
+	}
+
+	c := &Connector{
+		config:    *cfg,
+		client:    cfg.HTTPConfig.Client(),
+		firstSeen: make(map[string]time.Time),
+	}
+	if cfg.Nomad.URL != "" {
+		c.nomadClient = cfg.Nomad.HTTPConfig.Client()
+	}
+	return c
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	datacenters := c.config.Datacenters
+	if len(datacenters) == 0 {
+		var self agentSelf
+		if err := c.get(ctx, "/v1/agent/self", nil, &self); err != nil {
+			return nil, err
+		}
+		datacenters = []string{self.Config.Datacenter}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	firstSeen := make(map[string]time.Time)
+	var alerts []connectors.Alert
+	for _, dc := range datacenters {
+		// The "any" state is used to get warnings and critical checks at once
+		var checks []healthCheck
+		if err := c.get(ctx, "/v1/health/state/any", url.Values{"dc": {dc}}, &checks); err != nil {
+			return nil, err
+		}
+
+		for _, check := range checks {
+			state, ok := fromStatus(check.Status)
+			if !ok {
+				continue
+			}
+
+			key := dc + "/" + check.Node + "/" + check.CheckID
+			start, seen := c.firstSeen[key]
+			if !seen {
+				start = now
+			}
+			firstSeen[key] = start
+
+			descr := check.Name
+			if check.ServiceName != "" {
+				descr = check.ServiceName + ": " + check.Name
+			}
+
+			alert := connectors.Alert{
+				Labels: map[string]string{
+					"Datacenter": dc,
+					"Hostname":   check.Node,
+					"Service":    check.ServiceName,
+					"Check":      check.Name,
+					"CheckID":    check.CheckID,
+					"Source":     c.config.URL,
+					"Type":       "Check",
+				},
+				Start:       start,
+				State:       state,
+				Description: descr,
+				Details:     check.Output,
+				Links: []html.HTML{
+					html.HTML("<a href=\"" + c.config.URL + "/ui/" + url.PathEscape(dc) + "/nodes/" + url.PathEscape(check.Node) + "/health-checks\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+				},
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+	c.firstSeen = firstSeen
+
+	if c.nomadClient != nil {
+		nomadAlerts, err := c.collectNomad(ctx)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, nomadAlerts...)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Consul (%s)", c.config.URL)
+}
+
+func fromStatus(s status) (connectors.State, bool) {
+	switch s {
+	case statusWarning:
+		return connectors.Warning, true
+	case statusCritical:
+		return connectors.Critical, true
+	}
+	return connectors.OK, false
+}
+
+func (c *Connector) get(ctx context.Context, endpoint string, query url.Values, v any) error {
+	return c.getFrom(ctx, c.client, c.config.URL, endpoint, query, v)
+}
+
+func (c *Connector) getFrom(ctx context.Context, client *http.Client, baseURL, endpoint string, query url.Values, v any) error {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", baseURL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.URL.RawQuery = query.Encode()
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", baseURL+endpoint),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
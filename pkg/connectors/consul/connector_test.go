@@ -0,0 +1,156 @@
+package consul
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	consulServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var body string
+		switch req.URL.Path {
+		case "/v1/agent/self":
+			body = `{"Config": {"Datacenter": "dc1", "NodeName": "consul-1"}}`
+		case "/v1/health/state/any":
+			if req.URL.Query().Get("dc") != "dc1" {
+				t.Error("The datacenter of the agent should be queried", req.URL)
+			}
+			body = mockChecks
+		default:
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	}))
+	defer func() { consulServer.Close() }()
+
+	created := time.Now().Add(-time.Hour).UnixNano()
+	nomadServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer nomad-token" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.URL.Query().Get("namespace") != "*" {
+			t.Error("All namespaces should be queried", req.URL)
+		}
+
+		var body string
+		switch req.URL.Path {
+		case "/v1/allocations":
+			body = `[
+  {"ID": "a1", "Name": "web.app[0]", "Namespace": "default", "JobID": "web", "TaskGroup": "app", "NodeName": "worker-1", "ClientStatus": "failed", "ClientDescription": "Failed tasks", "DesiredStatus": "run", "NextAllocation": "", "CreateTime": ` + strconv.FormatInt(created, 10) + `},
+  {"ID": "a2", "Name": "web.app[1]", "Namespace": "default", "JobID": "web", "TaskGroup": "app", "NodeName": "worker-2", "ClientStatus": "failed", "DesiredStatus": "run", "NextAllocation": "a3", "CreateTime": ` + strconv.FormatInt(created, 10) + `},
+  {"ID": "a3", "Name": "web.app[1]", "Namespace": "default", "JobID": "web", "TaskGroup": "app", "NodeName": "worker-2", "ClientStatus": "running", "DesiredStatus": "run", "CreateTime": ` + strconv.FormatInt(created, 10) + `},
+  {"ID": "a4", "Name": "db.db[0]", "Namespace": "data", "JobID": "db", "TaskGroup": "db", "ClientStatus": "pending", "DesiredStatus": "run", "CreateTime": ` + strconv.FormatInt(created, 10) + `},
+  {"ID": "a5", "Name": "db.db[1]", "Namespace": "data", "JobID": "db", "TaskGroup": "db", "ClientStatus": "pending", "DesiredStatus": "run", "CreateTime": ` + strconv.FormatInt(time.Now().UnixNano(), 10) + `}
+]`
+		case "/v1/jobs":
+			body = `[
+  {"ID": "web", "Name": "web", "Namespace": "default", "Type": "service", "Status": "running", "Stop": false},
+  {"ID": "cache", "Name": "cache", "Namespace": "default", "Type": "service", "Status": "dead", "Stop": false, "SubmitTime": ` + strconv.FormatInt(created, 10) + `},
+  {"ID": "old", "Name": "old", "Namespace": "default", "Type": "service", "Status": "dead", "Stop": true},
+  {"ID": "backup", "Name": "backup", "Namespace": "default", "Type": "batch", "Status": "dead", "Stop": false}
+]`
+		default:
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	}))
+	defer func() { nomadServer.Close() }()
+
+	cfg := Config{
+		Tag: "test",
+		Nomad: NomadConfig{
+			HTTPConfig: common.HTTPConfig{
+				URL:         nomadServer.URL,
+				BearerToken: "nomad-token",
+			},
+		},
+		HTTPConfig: common.HTTPConfig{
+			URL: consulServer.URL,
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 5 {
+		t.Fatal("There should be alerts for checks, allocations and jobs", alerts)
+	}
+
+	if alerts[0].State != connectors.Critical || alerts[0].Labels["Service"] != "web" ||
+		alerts[0].Labels["Hostname"] != "worker-1" || alerts[0].Labels["Datacenter"] != "dc1" {
+		t.Error("Critical checks should be critical", alerts[0])
+	}
+	if alerts[1].State != connectors.Warning || alerts[1].Labels["Service"] != "" || alerts[1].Description != "Memory" {
+		t.Error("Node checks should be warnings without a service", alerts[1])
+	}
+	if alerts[2].Labels["Type"] != "Allocation" || alerts[2].State != connectors.Critical || alerts[2].Labels["Job"] != "web" {
+		t.Error("Failed allocations should be critical", alerts[2])
+	}
+	if alerts[3].Labels["Type"] != "Allocation" || alerts[3].State != connectors.Warning || alerts[3].Labels["Namespace"] != "data" {
+		t.Error("Long pending allocations should be warnings", alerts[3])
+	}
+	if alerts[4].Labels["Type"] != "Job" || alerts[4].Labels["Job"] != "cache" {
+		t.Error("Dead service jobs should be reported", alerts[4])
+	}
+
+	start := alerts[0].Start
+	alerts, err = connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !alerts[0].Start.Equal(start) {
+		t.Error("The start of a check should be kept between collections", start, alerts[0].Start)
+	}
+}
+
+const mockChecks = `
+[
+  {
+    "Node": "worker-1",
+    "CheckID": "service:web-1",
+    "Name": "Service 'web' check",
+    "Status": "critical",
+    "Output": "HTTP GET http://10.0.0.1:8080/health: 503 Service Unavailable",
+    "ServiceID": "web-1",
+    "ServiceName": "web",
+    "ServiceTags": ["v1"],
+    "Type": "http"
+  },
+  {
+    "Node": "worker-2",
+    "CheckID": "serfHealth",
+    "Name": "Serf Health Status",
+    "Status": "passing",
+    "Output": "Agent alive and reachable",
+    "ServiceID": "",
+    "ServiceName": "",
+    "Type": ""
+  },
+  {
+    "Node": "worker-2",
+    "CheckID": "mem",
+    "Name": "Memory",
+    "Status": "warning",
+    "Output": "92% used",
+    "ServiceID": "",
+    "ServiceName": "",
+    "Type": "script"
+  }
+]`
@@ -0,0 +1,94 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+	html "html/template"
+	"net/url"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+// collectNomad reports failed and long pending allocations, as well as
+// service jobs which are dead without being stopped.
+func (c *Connector) collectNomad(ctx context.Context) ([]connectors.Alert, error) {
+	nomad := c.config.Nomad
+	query := url.Values{"namespace": {"*"}}
+
+	var allocations []allocation
+	if err := c.getFrom(ctx, c.nomadClient, nomad.URL, "/v1/allocations", query, &allocations); err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, alloc := range allocations {
+		created := time.Unix(0, alloc.CreateTime)
+
+		var state connectors.State
+		var descr string
+		switch {
+		case alloc.ClientStatus == "failed" && alloc.DesiredStatus == "run" && alloc.NextAllocation == "":
+			// Rescheduled allocations have been replaced already
+			state = connectors.Critical
+			descr = "Allocation " + alloc.Name + " failed"
+		case alloc.ClientStatus == "pending" && time.Since(created) > nomad.PendingTimeout:
+			state = connectors.Warning
+			descr = "Allocation " + alloc.Name + " pending"
+		default:
+			continue
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Namespace": alloc.Namespace,
+				"Job":       alloc.JobID,
+				"TaskGroup": alloc.TaskGroup,
+				"Hostname":  alloc.NodeName,
+				"Status":    alloc.ClientStatus,
+				"Source":    nomad.URL,
+				"Type":      "Allocation",
+			},
+			Start:       created,
+			State:       state,
+			Description: descr,
+			Details:     alloc.ClientDesc,
+			Links: []html.HTML{
+				html.HTML("<a href=\"" + nomad.URL + "/ui/allocations/" + url.PathEscape(alloc.ID) + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			},
+		}
+		alerts = append(alerts, alert)
+	}
+
+	var jobs []job
+	if err := c.getFrom(ctx, c.nomadClient, nomad.URL, "/v1/jobs", query, &jobs); err != nil {
+		return nil, err
+	}
+
+	for _, j := range jobs {
+		// Batch jobs are dead after completing, stopped jobs are dead on purpose
+		if j.Status != "dead" || j.Stop || (j.Type != "service" && j.Type != "system") {
+			continue
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Namespace": j.Namespace,
+				"Job":       j.ID,
+				"Status":    j.Status,
+				"Source":    nomad.URL,
+				"Type":      "Job",
+			},
+			Start:       time.Unix(0, j.SubmitTime),
+			State:       connectors.Critical,
+			Description: fmt.Sprintf("Job %s is dead", j.Name),
+			Details:     j.StatusDescription,
+			Links: []html.HTML{
+				html.HTML("<a href=\"" + nomad.URL + "/ui/jobs/" + url.PathEscape(j.ID+"@"+j.Namespace) + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			},
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
@@ -0,0 +1,71 @@
+package docker
+
+// https://docs.docker.com/reference/api/engine/version/v1.45/#tag/Container/operation/ContainerList
+
+type containerSummary struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Image  string            `json:"Image"`
+	State  string            `json:"State"`
+	Status string            `json:"Status"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// https://docs.docker.com/reference/api/engine/version/v1.45/#tag/Container/operation/ContainerInspect
+
+type container struct {
+	ID           string         `json:"Id"`
+	Name         string         `json:"Name"`
+	RestartCount int            `json:"RestartCount"`
+	State        containerState `json:"State"`
+	Config       struct {
+		Image  string            `json:"Image"`
+		Labels map[string]string `json:"Labels"`
+	} `json:"Config"`
+	HostConfig struct {
+		RestartPolicy struct {
+			Name string `json:"Name"`
+		} `json:"RestartPolicy"`
+	} `json:"HostConfig"`
+}
+
+type containerState struct {
+	Status     string  `json:"Status"`
+	Running    bool    `json:"Running"`
+	Restarting bool    `json:"Restarting"`
+	OOMKilled  bool    `json:"OOMKilled"`
+	ExitCode   int     `json:"ExitCode"`
+	Error      string  `json:"Error"`
+	StartedAt  string  `json:"StartedAt"`
+	FinishedAt string  `json:"FinishedAt"`
+	Health     *health `json:"Health"`
+}
+
+type health struct {
+	Status        string        `json:"Status"`
+	FailingStreak int           `json:"FailingStreak"`
+	Log           []healthCheck `json:"Log"`
+}
+
+type healthCheck struct {
+	Start    string `json:"Start"`
+	End      string `json:"End"`
+	ExitCode int    `json:"ExitCode"`
+	Output   string `json:"Output"`
+}
+
+// https://docs.docker.com/reference/api/engine/version/v1.45/#tag/System/operation/SystemInfo
+
+type systemInfo struct {
+	Name string `json:"Name"`
+}
+
+const (
+	statusExited    = "exited"
+	restartAlways   = "always"
+	healthUnhealthy = "unhealthy"
+
+	// composeProjectLabel is set by docker compose and podman-compose
+	composeProjectLabel = "com.docker.compose.project"
+	composeServiceLabel = "com.docker.compose.service"
+)
@@ -0,0 +1,193 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	Tag string
+	// MaxRestarts is the restart count from which on a container is reported.
+	MaxRestarts int
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.URL == "" {
+		// Only the path of the URL is relevant when using the socket
+		cfg.URL = "http://localhost"
+		if cfg.Socket == "" {
+			cfg.Socket = "/var/run/docker.sock"
+		}
+	}
+	if cfg.MaxRestarts == 0 {
+		cfg.MaxRestarts = 5
+	}
+
+	return &Connector{*cfg, cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	var info systemInfo
+	if err := c.get(ctx, "/info", nil, &info); err != nil {
+		return nil, err
+	}
+
+	var summaries []containerSummary
+	if err := c.get(ctx, "/containers/json", url.Values{"all": {"true"}}, &summaries); err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, summary := range summaries {
+		var cont container
+		if err := c.get(ctx, "/containers/"+url.PathEscape(summary.ID)+"/json", nil, &cont); err != nil {
+			return nil, err
+		}
+
+		state, descr, details, start, ok := c.check(ctx, cont)
+		if !ok {
+			continue
+		}
+
+		name := strings.TrimPrefix(cont.Name, "/")
+
+		labels := make(map[string]string, len(cont.Config.Labels)+7)
+		for k, v := range cont.Config.Labels {
+			labels[k] = v
+		}
+		labels["Hostname"] = info.Name
+		labels["Container"] = name
+		labels["Image"] = cont.Config.Image
+		labels["Status"] = cont.State.Status
+		labels["Source"] = c.config.URL
+		labels["Type"] = "Container"
+		if project, ok := cont.Config.Labels[composeProjectLabel]; ok {
+			labels["Project"] = project
+		}
+		if service, ok := cont.Config.Labels[composeServiceLabel]; ok {
+			labels["Service"] = service
+		}
+
+		alert := connectors.Alert{
+			Labels:      labels,
+			Start:       start,
+			State:       state,
+			Description: fmt.Sprintf("Container %s %s", name, descr),
+			Details:     details,
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	if c.config.Socket != "" {
+		return fmt.Sprintf("Docker (%s)", c.config.Socket)
+	}
+	return fmt.Sprintf("Docker (%s)", c.config.URL)
+}
+
+// check determines whether a container is in trouble, the most severe reason
+// wins.
+func (c *Connector) check(ctx context.Context, cont container) (state connectors.State, descr, details string, start time.Time, ok bool) {
+	s := cont.State
+	switch {
+	case s.Health != nil && s.Health.Status == healthUnhealthy:
+		var output string
+		if len(s.Health.Log) > 0 {
+			output = strings.TrimSpace(s.Health.Log[len(s.Health.Log)-1].Output)
+		}
+		return connectors.Critical, "is unhealthy", output, parseTime(ctx, s.StartedAt), true
+	case s.Restarting:
+		return connectors.Critical, "is restarting", fmt.Sprintf("Restarted %d times, last exit code %d", cont.RestartCount, s.ExitCode), parseTime(ctx, s.FinishedAt), true
+	case s.Status == statusExited && s.ExitCode != 0 && !stopped(cont):
+		details := fmt.Sprintf("Exit code %d", s.ExitCode)
+		if s.OOMKilled {
+			details += ", killed due to out of memory"
+		}
+		if s.Error != "" {
+			details += ": " + s.Error
+		}
+		return connectors.Warning, "exited", details, parseTime(ctx, s.FinishedAt), true
+	case cont.RestartCount >= c.config.MaxRestarts:
+		return connectors.Warning, "restarts often", fmt.Sprintf("Restarted %d times", cont.RestartCount), parseTime(ctx, s.StartedAt), true
+	}
+	return connectors.OK, "", "", time.Time{}, false
+}
+
+// stopped returns whether the container was stopped deliberately, i.e. killed
+// via SIGKILL or SIGTERM by `docker stop` or `docker kill`.  Containers which
+// should always be running are not considered stopped.
+func stopped(cont container) bool {
+	s := cont.State
+	if s.OOMKilled || cont.HostConfig.RestartPolicy.Name == restartAlways {
+		return false
+	}
+	return s.ExitCode == 128+9 || s.ExitCode == 128+15
+}
+
+func parseTime(ctx context.Context, s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse", slog.String("time", s), slog.Any("error", err))
+		return time.Time{}
+	}
+	return t
+}
+
+func (c *Connector) get(ctx context.Context, endpoint string, query url.Values, v any) error {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.URL.RawQuery = query.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+endpoint),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
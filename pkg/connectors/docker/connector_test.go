@@ -0,0 +1,139 @@
+package docker
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockServer := &http.Server{Handler: http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var body string
+		switch req.URL.Path {
+		case "/info":
+			body = `{"Name": "docker-host-1"}`
+		case "/containers/json":
+			if req.URL.Query().Get("all") != "true" {
+				t.Error("Stopped containers should be listed as well", req.URL)
+			}
+			body = `[{"Id": "healthy"}, {"Id": "unhealthy"}, {"Id": "restarting"}, {"Id": "exited"}, {"Id": "stopped"}, {"Id": "flapping"}, {"Id": "killed"}, {"Id": "killed-always"}]`
+		default:
+			var ok bool
+			if body, ok = mockContainers[req.URL.Path]; !ok {
+				res.WriteHeader(http.StatusNotFound)
+				return
+			}
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	})}
+	go func() { _ = mockServer.Serve(listener) }()
+	defer func() { _ = mockServer.Close() }()
+
+	cfg := Config{
+		Tag: "test",
+		HTTPConfig: common.HTTPConfig{
+			Socket: socket,
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 5 {
+		t.Fatal("There should be alerts for troubled containers", alerts)
+	}
+
+	if alerts[0].Labels["Container"] != "shop-web-1" || alerts[0].State != connectors.Critical || alerts[0].Details != "connection refused" {
+		t.Error("Unhealthy containers should be critical", alerts[0])
+	}
+	if alerts[0].Labels["Project"] != "shop" || alerts[0].Labels["Service"] != "web" || alerts[0].Labels["team"] != "shop" {
+		t.Error("Container labels should be alert labels", alerts[0].Labels)
+	}
+	if alerts[0].Labels["Hostname"] != "docker-host-1" {
+		t.Error("The docker host should be the hostname", alerts[0].Labels)
+	}
+	if alerts[1].Labels["Container"] != "worker" || alerts[1].State != connectors.Critical {
+		t.Error("Restarting containers should be critical", alerts[1])
+	}
+	if alerts[2].Labels["Container"] != "migrate" || alerts[2].State != connectors.Warning || alerts[2].Details != "Exit code 137, killed due to out of memory" {
+		t.Error("Containers exited non-zero should be warnings", alerts[2])
+	}
+	if alerts[3].Labels["Container"] != "cron" || alerts[3].State != connectors.Warning {
+		t.Error("Containers with many restarts should be warnings", alerts[3])
+	}
+	if alerts[4].Labels["Container"] != "api" || alerts[4].Details != "Exit code 143" {
+		t.Error("Stopped containers should only be reported, if they should always run", alerts[4])
+	}
+}
+
+var mockContainers = map[string]string{
+	"/containers/healthy/json": `
+{
+  "Id": "healthy", "Name": "/db", "RestartCount": 0,
+  "State": {"Status": "running", "Running": true, "StartedAt": "2024-05-02T10:11:12.123456789Z", "Health": {"Status": "healthy"}},
+  "Config": {"Image": "postgres:16"}
+}`,
+	"/containers/unhealthy/json": `
+{
+  "Id": "unhealthy", "Name": "/shop-web-1", "RestartCount": 0,
+  "State": {
+    "Status": "running", "Running": true, "StartedAt": "2024-05-02T10:11:12.123456789Z",
+    "Health": {"Status": "unhealthy", "FailingStreak": 3, "Log": [{"ExitCode": 1, "Output": "timeout"}, {"ExitCode": 1, "Output": "connection refused\n"}]}
+  },
+  "Config": {"Image": "shop:1.2", "Labels": {"com.docker.compose.project": "shop", "com.docker.compose.service": "web", "team": "shop"}}
+}`,
+	"/containers/restarting/json": `
+{
+  "Id": "restarting", "Name": "/worker", "RestartCount": 2,
+  "State": {"Status": "restarting", "Restarting": true, "ExitCode": 1, "FinishedAt": "2024-05-02T10:11:12Z"},
+  "Config": {"Image": "worker:latest"}
+}`,
+	"/containers/exited/json": `
+{
+  "Id": "exited", "Name": "/migrate", "RestartCount": 0,
+  "State": {"Status": "exited", "ExitCode": 137, "OOMKilled": true, "FinishedAt": "2024-05-02T10:11:12Z"},
+  "Config": {"Image": "shop:1.2"}
+}`,
+	"/containers/stopped/json": `
+{
+  "Id": "stopped", "Name": "/oneshot", "RestartCount": 0,
+  "State": {"Status": "exited", "ExitCode": 0, "FinishedAt": "2024-05-02T10:11:12Z"},
+  "Config": {"Image": "busybox"}
+}`,
+	"/containers/flapping/json": `
+{
+  "Id": "flapping", "Name": "/cron", "RestartCount": 7,
+  "State": {"Status": "running", "Running": true, "StartedAt": "2024-05-02T10:11:12Z"},
+  "Config": {"Image": "cron"}
+}`,
+	"/containers/killed/json": `
+{
+  "Id": "killed", "Name": "/debug", "RestartCount": 0,
+  "State": {"Status": "exited", "ExitCode": 137, "FinishedAt": "2024-05-02T10:11:12Z"},
+  "Config": {"Image": "busybox"},
+  "HostConfig": {"RestartPolicy": {"Name": "unless-stopped"}}
+}`,
+	"/containers/killed-always/json": `
+{
+  "Id": "killed-always", "Name": "/api", "RestartCount": 0,
+  "State": {"Status": "exited", "ExitCode": 143, "FinishedAt": "2024-05-02T10:11:12Z"},
+  "Config": {"Image": "shop:1.2"},
+  "HostConfig": {"RestartPolicy": {"Name": "always"}}
+}`,
+}
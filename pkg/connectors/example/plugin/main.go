@@ -0,0 +1,61 @@
+// Command plugin is a reference implementation of a tuwat plugin.  It serves
+// the same alerts as the example connector.
+//
+//	[[plugin]]
+//	Tag = "demo"
+//	Command = "/usr/local/bin/tuwat-example-plugin"
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors/example"
+	"github.com/synyx/tuwat/pkg/connectors/plugin"
+)
+
+type handler struct {
+	connector *example.Connector
+}
+
+func (h *handler) Collect(ctx context.Context) ([]plugin.Alert, error) {
+	exampleAlerts, err := h.connector.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []plugin.Alert
+	for i, a := range exampleAlerts {
+		alerts = append(alerts, plugin.Alert{
+			ID:          fmt.Sprintf("example-%d", i),
+			Labels:      a.Labels,
+			Start:       a.Start,
+			State:       a.State,
+			Description: a.Description,
+			Details:     a.Details,
+			Links: []plugin.Link{
+				{URL: "https://go.dev/", Title: "🏠"},
+			},
+		})
+	}
+	return alerts, nil
+}
+
+func (h *handler) Silence(ctx context.Context, id string, duration time.Duration, user string) error {
+	slog.InfoContext(ctx, "silencing", slog.String("id", id), slog.Duration("duration", duration), slog.String("user", user))
+	return nil
+}
+
+func main() {
+	// stdout is reserved for the protocol
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	h := &handler{connector: example.NewConnector(&example.Config{})}
+	if err := plugin.Serve(context.Background(), "example", h, os.Stdin, os.Stdout); err != nil {
+		slog.Error("serving failed", slog.Any("error", err))
+		os.Exit(1)
+	}
+}
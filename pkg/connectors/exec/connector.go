@@ -0,0 +1,361 @@
+/*
+Package exec runs local commands compatible with nagios plugins.
+
+The exit code of the command is mapped onto the alert state, the first line
+of output becomes the description and the long output the details.  Every
+check runs in its own interval, independent of the collection interval, and
+changed results are announced immediately.
+
+see https://nagios-plugins.org/doc/guidelines.html
+*/
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	osexec "os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+type Connector struct {
+	config    Config
+	checks    []*check
+	sem       chan struct{}
+	streaming atomic.Bool // whether the checks are scheduled by Stream
+}
+
+type Config struct {
+	Tag string
+	// Interval is the default interval in which checks are run.
+	Interval time.Duration
+	// Timeout is the default timeout after which a check is killed.
+	Timeout time.Duration
+	// Concurrency limits the number of checks running in parallel.
+	Concurrency int
+	// Vars are available in argument and environment templates as `.Vars`.
+	Vars   map[string]string
+	Checks []CheckConfig
+}
+
+type CheckConfig struct {
+	Name    string
+	Command string
+	// Args and Env are golang `text/template`s, see templateData.
+	Args     []string
+	Env      map[string]string
+	Labels   map[string]string
+	Interval time.Duration
+	Timeout  time.Duration
+}
+
+// templateData is handed to argument and environment templates.
+type templateData struct {
+	Name     string
+	Tag      string
+	Hostname string
+	Vars     map[string]string
+}
+
+type check struct {
+	config CheckConfig
+	args   []*template.Template
+	env    map[string]*template.Template
+
+	mu      sync.Mutex // Protecting the result and the scheduling fields
+	running bool
+	lastRun time.Time
+	result  *result
+}
+
+type result struct {
+	state    connectors.State
+	since    time.Time
+	text     string
+	longText string
+	perfdata map[string]string
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.Interval == 0 {
+		cfg.Interval = 1 * time.Minute
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Concurrency == 0 {
+		cfg.Concurrency = 4
+	}
+
+	c := &Connector{
+		config: *cfg,
+		sem:    make(chan struct{}, cfg.Concurrency),
+	}
+
+	funcs := template.FuncMap{"env": os.Getenv}
+	for _, checkConfig := range cfg.Checks {
+		if checkConfig.Interval == 0 {
+			checkConfig.Interval = cfg.Interval
+		}
+		if checkConfig.Timeout == 0 {
+			checkConfig.Timeout = cfg.Timeout
+		}
+		if checkConfig.Name == "" {
+			checkConfig.Name = checkConfig.Command
+		}
+
+		ch := &check{config: checkConfig, env: make(map[string]*template.Template)}
+		for _, arg := range checkConfig.Args {
+			ch.args = append(ch.args, template.Must(template.New("arg").Funcs(funcs).Parse(arg)))
+		}
+		for name, value := range checkConfig.Env {
+			ch.env[name] = template.Must(template.New(name).Funcs(funcs).Parse(value))
+		}
+		c.checks = append(c.checks, ch)
+	}
+
+	return c
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+// Stream runs every check in its own interval, announcing changed results.
+// Collections then only report the latest results.
+func (c *Connector) Stream(ctx context.Context, changed func()) {
+	c.streaming.Store(true)
+
+	var wg sync.WaitGroup
+	for _, ch := range c.checks {
+		wg.Go(func() {
+			c.schedule(ctx, ch, changed)
+		})
+	}
+	wg.Wait()
+}
+
+func (c *Connector) schedule(ctx context.Context, ch *check, changed func()) {
+	for {
+		if ch.due(time.Now()) && c.run(ch) {
+			changed()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ch.next(time.Now())):
+		}
+	}
+}
+
+// Collect starts all checks which are due and waits for them, as long as the
+// context allows.  Checks still running afterwards will report on a later
+// collection.  While streaming, the checks are not started by collections.
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	var wg sync.WaitGroup
+	for _, ch := range c.checks {
+		if c.streaming.Load() || !ch.due(time.Now()) {
+			continue
+		}
+		wg.Go(func() {
+			c.run(ch)
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		slog.InfoContext(ctx, "checks still running, reporting last results", slog.String("tag", c.config.Tag))
+	}
+
+	hostname, _ := os.Hostname()
+
+	var alerts []connectors.Alert
+	for _, ch := range c.checks {
+		ch.mu.Lock()
+		r := ch.result
+		ch.mu.Unlock()
+
+		if r == nil || r.state == connectors.OK {
+			continue
+		}
+
+		labels := make(map[string]string)
+		for k, v := range r.perfdata {
+			labels[k] = v
+		}
+		for k, v := range ch.config.Labels {
+			labels[k] = v
+		}
+		labels["Hostname"] = hostname
+		labels["Check"] = ch.config.Name
+		labels["Command"] = ch.config.Command
+		labels["Type"] = "Exec"
+
+		alert := connectors.Alert{
+			Labels:      labels,
+			Start:       r.since,
+			State:       r.state,
+			Description: r.text,
+			Details:     r.longText,
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Exec (%d checks)", len(c.checks))
+}
+
+// due marks the check as running, if it is not already running and its last
+// run is older than its interval.
+func (ch *check) due(now time.Time) bool {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if ch.running || now.Before(ch.lastRun.Add(ch.config.Interval)) {
+		return false
+	}
+	ch.running = true
+	return true
+}
+
+// next returns the time until the check is due again.
+func (ch *check) next(now time.Time) time.Duration {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+
+	if ch.running {
+		return ch.config.Interval
+	}
+	return ch.lastRun.Add(ch.config.Interval).Sub(now)
+}
+
+// run runs the check, returning whether its state or output changed.
+func (c *Connector) run(ch *check) bool {
+	c.sem <- struct{}{}
+	defer func() { <-c.sem }()
+
+	// The check is not bound to the collection, as its result is kept for
+	// later collections.
+	ctx, cancel := context.WithTimeout(context.Background(), ch.config.Timeout)
+	defer cancel()
+
+	r := c.execute(ctx, ch)
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.running = false
+	ch.lastRun = time.Now()
+	changed := ch.result == nil || ch.result.state != r.state || ch.result.text != r.text
+	if ch.result != nil && ch.result.state == r.state {
+		r.since = ch.result.since
+	}
+	ch.result = r
+
+	return changed
+}
+
+func (c *Connector) execute(ctx context.Context, ch *check) *result {
+	now := time.Now()
+	hostname, _ := os.Hostname()
+	data := templateData{
+		Name:     ch.config.Name,
+		Tag:      c.config.Tag,
+		Hostname: hostname,
+		Vars:     c.config.Vars,
+	}
+
+	var args []string
+	for _, t := range ch.args {
+		arg, err := render(t, data)
+		if err != nil {
+			return &result{state: connectors.Unknown, since: now, text: "Cannot render arguments", longText: err.Error()}
+		}
+		args = append(args, arg)
+	}
+	env := os.Environ()
+	for name, t := range ch.env {
+		value, err := render(t, data)
+		if err != nil {
+			return &result{state: connectors.Unknown, since: now, text: "Cannot render environment", longText: err.Error()}
+		}
+		env = append(env, name+"="+value)
+	}
+
+	slog.DebugContext(ctx, "running check",
+		slog.String("check", ch.config.Name),
+		slog.String("command", ch.config.Command),
+		slog.String("args", strings.Join(args, " ")))
+
+	cmd := osexec.CommandContext(ctx, ch.config.Command, args...)
+	cmd.Env = env
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Do not wait for grandchildren holding on to stdout after a timeout.
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+
+	if ctx.Err() != nil {
+		return &result{state: connectors.Unknown, since: now, text: fmt.Sprintf("Check timed out after %s", ch.config.Timeout)}
+	}
+
+	var exitErr *osexec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return &result{state: connectors.Unknown, since: now, text: "Cannot run check", longText: err.Error()}
+	}
+
+	text, longText, perfdata := parseOutput(stdout.String())
+	if text == "" {
+		text = strings.TrimSpace(stderr.String())
+	}
+
+	return &result{
+		state:    fromExitCode(cmd.ProcessState.ExitCode()),
+		since:    now,
+		text:     text,
+		longText: longText,
+		perfdata: perfdata,
+	}
+}
+
+func render(t *template.Template, data templateData) (string, error) {
+	buf := new(strings.Builder)
+	if err := t.Execute(buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// see: https://nagios-plugins.org/doc/guidelines.html#AEN78
+func fromExitCode(code int) connectors.State {
+	switch code {
+	case 0:
+		return connectors.OK
+	case 1:
+		return connectors.Warning
+	case 2:
+		return connectors.Critical
+	default:
+		return connectors.Unknown
+	}
+}
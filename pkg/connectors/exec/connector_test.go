@@ -0,0 +1,143 @@
+package exec
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+func TestConnector(t *testing.T) {
+	cfg := Config{
+		Tag:  "test",
+		Vars: map[string]string{"path": "/var"},
+		Checks: []CheckConfig{
+			{
+				Name:    "ok",
+				Command: "/bin/sh",
+				Args:    []string{"-c", "echo 'DISK OK'; exit 0"},
+			},
+			{
+				Name:    "disk",
+				Command: "/bin/sh",
+				Args:    []string{"-c", `printf 'DISK WARNING - {{.Vars.path}} | {{.Vars.path}}=91%%;80;90\nlong output\n'; exit 1`},
+				Labels:  map[string]string{"Project": "backup"},
+			},
+			{
+				Name:    "env",
+				Command: "/bin/sh",
+				Args:    []string{"-c", `echo "BACKUP CRITICAL - $MARKER"; exit 2`},
+				Env:     map[string]string{"MARKER": "{{.Name}} missing"},
+			},
+			{
+				Name:    "missing",
+				Command: "/nonexistent",
+			},
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 3 {
+		t.Fatal("There should be alerts, but OK checks are skipped:", alerts)
+	}
+
+	disk := alerts[0]
+	if disk.State != connectors.Warning || disk.Description != "DISK WARNING - /var" || disk.Details != "long output" {
+		t.Error("Output should be parsed", disk)
+	}
+	if disk.Labels["/var"] != "91%" || disk.Labels["Project"] != "backup" {
+		t.Error("Labels should contain perfdata and configured labels", disk.Labels)
+	}
+
+	if alerts[1].State != connectors.Critical || alerts[1].Description != "BACKUP CRITICAL - env missing" {
+		t.Error("Environment should be templated", alerts[1])
+	}
+
+	if alerts[2].State != connectors.Unknown {
+		t.Error("Failing to run should be unknown", alerts[2])
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	cfg := Config{
+		Tag:     "test",
+		Timeout: 100 * time.Millisecond,
+		Checks: []CheckConfig{
+			{Command: "/bin/sleep", Args: []string{"10"}},
+		},
+	}
+
+	alerts, err := NewConnector(&cfg).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 1 || alerts[0].State != connectors.Unknown {
+		t.Error("Timeout should be reported as unknown", alerts)
+	}
+}
+
+func TestStream(t *testing.T) {
+	counter := t.TempDir() + "/counter"
+	cfg := Config{
+		Tag: "test",
+		Checks: []CheckConfig{
+			{
+				Name:     "counter",
+				Command:  "/bin/sh",
+				Args:     []string{"-c", `echo run >> ` + counter + `; echo "RUNS WARNING - $(wc -l < ` + counter + `)"; exit 1`},
+				Interval: 20 * time.Millisecond,
+			},
+		},
+	}
+	connector := NewConnector(&cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changed := make(chan bool, 10)
+	go connector.Stream(ctx, func() { changed <- true })
+
+	for range 3 {
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			t.Fatal("Checks should run in their own interval and announce changes")
+		}
+	}
+
+	alerts, err := connector.Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || alerts[0].Description == "RUNS WARNING - 1" {
+		t.Error("Collections should report the latest results", alerts)
+	}
+}
+
+func TestParseOutput(t *testing.T) {
+	text, long, perf := parseOutput("DISK OK - free space: / 3326 MB (56%); | /=2643MB;5948;5958;0;5968\n/ 15272 MB (77%);\n/boot 68 MB (69%);\n/home 69357 MB (27%);\n/var/log 819 MB (84%); | /boot=68MB;88;93;0;98\n/home=69357MB;253404;253409;0;253414 \n'/var log'=818MB;970;975;0;980\n")
+
+	if text != "DISK OK - free space: / 3326 MB (56%);" {
+		t.Error("unexpected text", text)
+	}
+	if long != "/ 15272 MB (77%);\n/boot 68 MB (69%);\n/home 69357 MB (27%);\n/var/log 819 MB (84%);" {
+		t.Error("unexpected long text", long)
+	}
+
+	expected := map[string]string{"/": "2643MB", "/boot": "68MB", "/home": "69357MB", "/var log": "818MB"}
+	if len(perf) != len(expected) {
+		t.Error("unexpected perfdata", perf)
+	}
+	for k, v := range expected {
+		if perf[k] != v {
+			t.Errorf("perfdata %s should be %s, got %s", k, v, perf[k])
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package exec
+
+import (
+	"strings"
+)
+
+// parseOutput splits the output of a nagios plugin into the first line, the
+// long output and the performance data.
+//
+// see https://nagios-plugins.org/doc/guidelines.html#AEN33
+func parseOutput(output string) (text, longText string, perfdata map[string]string) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+
+	var perf []string
+	text, p, _ := strings.Cut(lines[0], "|")
+	text = strings.TrimSpace(text)
+	perf = append(perf, p)
+
+	var long []string
+	inPerf := false
+	for _, line := range lines[1:] {
+		if inPerf {
+			perf = append(perf, line)
+		} else if l, p, found := strings.Cut(line, "|"); found {
+			long = append(long, l)
+			perf = append(perf, p)
+			inPerf = true
+		} else {
+			long = append(long, line)
+		}
+	}
+
+	longText = strings.TrimSpace(strings.Join(long, "\n"))
+	perfdata = parsePerfdata(strings.Join(perf, " "))
+
+	return text, longText, perfdata
+}
+
+// parsePerfdata parses `'label'=value[UOM];[warn];[crit];[min];[max]` pairs.
+// Only the value including its unit is kept.
+func parsePerfdata(perf string) map[string]string {
+	result := make(map[string]string)
+
+	for perf = strings.TrimSpace(perf); perf != ""; perf = strings.TrimSpace(perf) {
+		var label string
+		if strings.HasPrefix(perf, "'") {
+			end := strings.Index(perf[1:], "'=")
+			if end < 0 {
+				break
+			}
+			label = perf[1 : end+1]
+			perf = perf[end+3:]
+		} else {
+			l, rest, found := strings.Cut(perf, "=")
+			if !found {
+				break
+			}
+			label = l
+			perf = rest
+		}
+
+		value, rest, _ := strings.Cut(perf, " ")
+		perf = rest
+
+		value, _, _ = strings.Cut(value, ";")
+		if label != "" && value != "" {
+			result[label] = value
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,306 @@
+/*
+Package genericjson collects alerts from any HTTP endpoint returning a JSON
+list of problems.
+
+Items are selected via a JSONPath expression and mapped onto alerts via
+golang `text/template`s, which get the item as data.  Within templates
+`path` can be used to evaluate a JSONPath expression on an item, e.g.
+`{{path . "$.host.name"}}`.
+*/
+package genericjson
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+
+	items       jsonPath
+	nextLink    jsonPath
+	description *template.Template
+	details     *template.Template
+	start       *template.Template
+	state       *template.Template
+	labels      map[string]*template.Template
+	links       []*template.Template
+}
+
+type Config struct {
+	Tag string
+	common.HTTPConfig
+
+	// Items is a JSONPath expression selecting the alerts in the response.
+	Items string
+	// NextLink is a JSONPath expression selecting the URL of the next page.
+	NextLink string
+	MaxPages int
+
+	Description string
+	Details     string
+	// Start is rendered into either a unix timestamp or a time in
+	// StartLayout.  Timestamps are read as seconds, unless StartLayout is
+	// `unixms` for milliseconds.
+	Start       string
+	StartLayout string
+	// State is rendered and mapped via States onto `ok`, `warning`,
+	// `critical` or `unknown`.  Without a mapping, those names and the
+	// numbers 0-3 are accepted directly.
+	State  string
+	States map[string]string
+	Labels map[string]string
+	Links  []string
+}
+
+// layoutUnixMilli reads numeric starts as unix milliseconds.
+const layoutUnixMilli = "unixms"
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.Items == "" {
+		cfg.Items = "$[*]"
+	}
+	if cfg.MaxPages == 0 {
+		cfg.MaxPages = 10
+	}
+	if cfg.StartLayout == "" {
+		cfg.StartLayout = time.RFC3339
+	}
+	if cfg.Description == "" {
+		cfg.Description = "{{.description}}"
+	}
+	if cfg.State == "" {
+		cfg.State = "warning"
+	}
+
+	c := &Connector{
+		config: *cfg,
+		client: cfg.HTTPConfig.Client(),
+		labels: make(map[string]*template.Template),
+	}
+
+	c.items = mustParseJSONPath(cfg.Items)
+	if cfg.NextLink != "" {
+		c.nextLink = mustParseJSONPath(cfg.NextLink)
+	}
+
+	c.description = mustParseTemplate("description", cfg.Description)
+	c.details = mustParseTemplate("details", cfg.Details)
+	c.start = mustParseTemplate("start", cfg.Start)
+	c.state = mustParseTemplate("state", cfg.State)
+	for name, text := range cfg.Labels {
+		c.labels[name] = mustParseTemplate(name, text)
+	}
+	for _, text := range cfg.Links {
+		c.links = append(c.links, mustParseTemplate("link", text))
+	}
+
+	return c
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	items, err := c.collectItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, item := range items {
+		stateText := c.render(ctx, c.state, item)
+		state, ok := c.mapState(stateText)
+		if !ok {
+			slog.ErrorContext(ctx, "Cannot parse: Unknown state", slog.String("state", stateText))
+		}
+		if state == connectors.OK {
+			continue
+		}
+
+		labels := map[string]string{
+			"Source": c.config.URL,
+		}
+		for name, t := range c.labels {
+			labels[name] = c.render(ctx, t, item)
+		}
+
+		var links []html.HTML
+		for _, t := range c.links {
+			if link := c.render(ctx, t, item); link != "" {
+				links = append(links, html.HTML("<a href=\""+html.HTMLEscapeString(link)+"\" target=\"_blank\" alt=\"Home\">🏠</a>"))
+			}
+		}
+
+		alert := connectors.Alert{
+			Labels:      labels,
+			Start:       c.parseStart(ctx, c.render(ctx, c.start, item)),
+			State:       state,
+			Description: c.render(ctx, c.description, item),
+			Details:     c.render(ctx, c.details, item),
+			Links:       links,
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("JSON (%s)", c.config.URL)
+}
+
+// collectItems gets all pages, following the next link if configured.
+func (c *Connector) collectItems(ctx context.Context) ([]any, error) {
+	var items []any
+
+	next := c.config.URL
+	for page := 0; next != "" && page < c.config.MaxPages; page++ {
+		document, err := c.get(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, c.items.eval(document)...)
+
+		if c.nextLink == nil {
+			break
+		}
+		link := c.nextLink.first(document)
+		if link == "" {
+			break
+		}
+		base, _ := url.Parse(next)
+		ref, err := url.Parse(link)
+		if err != nil {
+			return nil, err
+		}
+		next = base.ResolveReference(ref).String()
+	}
+
+	return items, nil
+}
+
+func (c *Connector) get(ctx context.Context, endpoint string) (any, error) {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to get, unknown status code: %d", res.StatusCode)
+	}
+
+	b, _ := io.ReadAll(res.Body)
+	buf := bytes.NewBuffer(b)
+
+	decoder := json.NewDecoder(buf)
+	// Keep numbers as they are, e.g. so timestamps do not render as floats.
+	decoder.UseNumber()
+
+	var document any
+	err = decoder.Decode(&document)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", endpoint),
+			slog.String("data", buf.String()),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return nil, err
+	}
+
+	return document, nil
+}
+
+func (c *Connector) render(ctx context.Context, t *template.Template, item any) string {
+	buf := new(strings.Builder)
+	if err := t.Execute(buf, item); err != nil {
+		slog.ErrorContext(ctx, "Cannot render", slog.String("template", t.Name()), slog.Any("error", err))
+		return ""
+	}
+	// Missing keys on maps render as "<no value>".
+	return strings.TrimSpace(strings.ReplaceAll(buf.String(), "<no value>", ""))
+}
+
+func (c *Connector) parseStart(ctx context.Context, start string) time.Time {
+	if start == "" {
+		return time.Time{}
+	}
+	if timestamp, err := strconv.ParseFloat(start, 64); err == nil {
+		if c.config.StartLayout == layoutUnixMilli {
+			return time.UnixMilli(int64(timestamp))
+		}
+		return time.UnixMilli(int64(timestamp * 1000))
+	}
+	t, err := time.Parse(c.config.StartLayout, start)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse", slog.String("start", start), slog.Any("error", err))
+	}
+	return t
+}
+
+func (c *Connector) mapState(state string) (connectors.State, bool) {
+	if mapped, ok := c.config.States[state]; ok {
+		state = mapped
+	}
+
+	switch strings.ToLower(state) {
+	case "ok", "0":
+		return connectors.OK, true
+	case "warning", "1":
+		return connectors.Warning, true
+	case "critical", "2":
+		return connectors.Critical, true
+	case "unknown", "3":
+		return connectors.Unknown, true
+	}
+	return connectors.Unknown, false
+}
+
+func mustParseJSONPath(expr string) jsonPath {
+	p, err := parseJSONPath(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func mustParseTemplate(name, text string) *template.Template {
+	return template.Must(template.New(name).
+		Funcs(map[string]any{
+			"path": func(item any, expr string) (string, error) {
+				p, err := parseJSONPath(expr)
+				if err != nil {
+					return "", err
+				}
+				return p.first(item), nil
+			},
+		}).
+		Parse(text))
+}
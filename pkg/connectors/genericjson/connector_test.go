@@ -0,0 +1,165 @@
+package genericjson
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		res.WriteHeader(http.StatusOK)
+		if req.URL.Query().Get("page") == "2" {
+			_, _ = res.Write([]byte(mockResponsePage2))
+		} else {
+			_, _ = res.Write([]byte(mockResponsePage1))
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag: "test",
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL + "/api/problems",
+		},
+		Items:       "$.data.problems[*]",
+		NextLink:    "$.links.next",
+		Description: "{{.title}}",
+		Details:     "{{.details.text}}",
+		Start:       "{{.since}}",
+		State:       "{{.level}}",
+		States:      map[string]string{"high": "critical", "low": "warning", "none": "ok"},
+		Labels: map[string]string{
+			"Hostname": `{{path . "$.host.name"}}`,
+		},
+		Links: []string{"https://tool.example.com/problems/{{.id}}"},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts from both pages, without OK ones:", alerts)
+	}
+
+	alert := alerts[0]
+	if alert.Description != "Disk full" || alert.Details != "/var at 100%" || alert.State != connectors.Critical {
+		t.Error("Fields should be mapped", alert)
+	}
+	if alert.Labels["Hostname"] != "db1.example.com" {
+		t.Error("Labels should be mapped", alert.Labels)
+	}
+	if !alert.Start.Equal(time.Unix(1700000000, 0)) {
+		t.Error("Start should be parsed from unix seconds", alert.Start)
+	}
+	if len(alert.Links) != 1 || !strings.Contains(string(alert.Links[0]), "/problems/17") {
+		t.Error("Links should be rendered", alert.Links)
+	}
+
+	if alerts[1].State != connectors.Warning || alerts[1].Start.Year() != 2024 {
+		t.Error("Second page should be mapped", alerts[1])
+	}
+}
+
+func TestParseStart(t *testing.T) {
+	tests := []struct {
+		layout string
+		start  string
+		want   time.Time
+	}{
+		{"", "1700000000", time.Unix(1700000000, 0)},
+		{"", "1700000000.5", time.UnixMilli(1700000000500)},
+		{"", "2024-01-02T03:04:05Z", time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{"unixms", "1700000000123", time.UnixMilli(1700000000123)},
+		{"unixms", "1.700000000123e+12", time.UnixMilli(1700000000123)},
+		{"2006-01-02", "2024-01-02", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		c := NewConnector(&Config{StartLayout: tt.layout})
+		if got := c.parseStart(context.Background(), tt.start); !got.Equal(tt.want) {
+			t.Errorf("%q in layout %q should be %s, got %s", tt.start, tt.layout, tt.want, got)
+		}
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	document := map[string]any{
+		"a": []any{
+			map[string]any{"b": "first"},
+			map[string]any{"b": "second", "c d": "spaced"},
+		},
+	}
+
+	tests := map[string]string{
+		"$.a[0].b":       "first",
+		"$.a[-1].b":      "second",
+		"$.a[1]['c d']":  "spaced",
+		"$['a'][*].b":    "first",
+		"$.missing.path": "",
+	}
+	for expr, expected := range tests {
+		p, err := parseJSONPath(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := p.first(document); actual != expected {
+			t.Errorf("%s should be %q, got %q", expr, expected, actual)
+		}
+	}
+
+	if _, err := parseJSONPath("$.a[?(@.b)]"); err == nil {
+		t.Error("Filters are not supported and should fail")
+	}
+}
+
+const mockResponsePage1 = `
+{
+  "data": {
+    "problems": [
+      {
+        "id": 17,
+        "title": "Disk full",
+        "details": { "text": "/var at 100%" },
+        "since": 1700000000,
+        "level": "high",
+        "host": { "name": "db1.example.com" }
+      },
+      {
+        "id": 18,
+        "title": "All good",
+        "since": 1700000000,
+        "level": "none",
+        "host": { "name": "db2.example.com" }
+      }
+    ]
+  },
+  "links": { "next": "/api/problems?page=2" }
+}
+`
+
+const mockResponsePage2 = `
+{
+  "data": {
+    "problems": [
+      {
+        "id": 19,
+        "title": "Certificate expires soon",
+        "since": "2024-01-02T03:04:05Z",
+        "level": "low",
+        "host": { "name": "www.example.com" }
+      }
+    ]
+  },
+  "links": { "next": null }
+}
+`
@@ -0,0 +1,119 @@
+package genericjson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// step is a single step of a JSONPath expression: either a member name, an
+// array index or a wildcard.
+type step struct {
+	name     string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// jsonPath is a small subset of JSONPath, supporting member access via
+// `.name` or `['name']`, array indices `[0]` and wildcards `[*]`/`.*`.
+//
+// see https://www.rfc-editor.org/rfc/rfc9535
+type jsonPath []step
+
+func parseJSONPath(expr string) (jsonPath, error) {
+	expr = strings.TrimSpace(expr)
+	expr = strings.TrimPrefix(expr, "$")
+
+	var path jsonPath
+	for len(expr) > 0 {
+		switch expr[0] {
+		case '.':
+			expr = expr[1:]
+			end := strings.IndexAny(expr, ".[")
+			if end < 0 {
+				end = len(expr)
+			}
+			name := expr[:end]
+			expr = expr[end:]
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath: empty member name")
+			} else if name == "*" {
+				path = append(path, step{wildcard: true})
+			} else {
+				path = append(path, step{name: name})
+			}
+		case '[':
+			end := strings.Index(expr, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath: unterminated bracket in %q", expr)
+			}
+			selector := expr[1:end]
+			expr = expr[end+1:]
+			if selector == "*" {
+				path = append(path, step{wildcard: true})
+			} else if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+				path = append(path, step{name: selector[1 : len(selector)-1]})
+			} else if i, err := strconv.Atoi(selector); err == nil {
+				path = append(path, step{index: i, isIndex: true})
+			} else {
+				return nil, fmt.Errorf("jsonpath: unsupported selector %q", selector)
+			}
+		default:
+			return nil, fmt.Errorf("jsonpath: unexpected %q", expr)
+		}
+	}
+
+	return path, nil
+}
+
+// eval returns all nodes selected by the path.
+func (p jsonPath) eval(document any) []any {
+	nodes := []any{document}
+	for _, s := range p {
+		var next []any
+		for _, node := range nodes {
+			switch n := node.(type) {
+			case map[string]any:
+				if s.wildcard {
+					for _, v := range n {
+						next = append(next, v)
+					}
+				} else if v, ok := n[s.name]; ok && !s.isIndex {
+					next = append(next, v)
+				}
+			case []any:
+				if s.wildcard {
+					next = append(next, n...)
+				} else if s.isIndex {
+					i := s.index
+					if i < 0 {
+						i += len(n)
+					}
+					if i >= 0 && i < len(n) {
+						next = append(next, n[i])
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// first returns the first selected node formatted as string, or "" if there
+// is none.
+func (p jsonPath) first(document any) string {
+	nodes := p.eval(document)
+	if len(nodes) == 0 || nodes[0] == nil {
+		return ""
+	}
+	switch v := nodes[0].(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
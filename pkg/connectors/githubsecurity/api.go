@@ -0,0 +1,94 @@
+package githubsecurity
+
+// https://docs.github.com/en/rest/repos/repos#get-a-repository
+
+type repository struct {
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+	HTMLUrl       string `json:"html_url"`
+}
+
+// https://docs.github.com/en/rest/actions/workflow-runs#list-workflow-runs-for-a-repository
+
+type workflowRuns struct {
+	TotalCount   int           `json:"total_count"`
+	WorkflowRuns []workflowRun `json:"workflow_runs"`
+}
+
+type workflowRun struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	WorkflowID   int64  `json:"workflow_id"`
+	HeadBranch   string `json:"head_branch"`
+	HeadSha      string `json:"head_sha"`
+	Event        string `json:"event"`
+	Status       string `json:"status"`
+	Conclusion   string `json:"conclusion"`
+	RunNumber    int    `json:"run_number"`
+	HTMLUrl      string `json:"html_url"`
+	UpdatedAt    string `json:"updated_at"`
+	DisplayTitle string `json:"display_title"`
+	Actor        user   `json:"actor"`
+}
+
+type user struct {
+	Login string `json:"login"`
+}
+
+// https://docs.github.com/en/rest/dependabot/alerts#list-dependabot-alerts-for-a-repository
+
+type dependabotAlert struct {
+	Number     int    `json:"number"`
+	State      string `json:"state"`
+	HTMLUrl    string `json:"html_url"`
+	CreatedAt  string `json:"created_at"`
+	Dependency struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		ManifestPath string `json:"manifest_path"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		GHSAID   string `json:"ghsa_id"`
+		CVEID    string `json:"cve_id"`
+		Summary  string `json:"summary"`
+		Severity string `json:"severity"`
+	} `json:"security_advisory"`
+	SecurityVulnerability struct {
+		Severity               string `json:"severity"`
+		VulnerableVersionRange string `json:"vulnerable_version_range"`
+		FirstPatchedVersion    *struct {
+			Identifier string `json:"identifier"`
+		} `json:"first_patched_version"`
+	} `json:"security_vulnerability"`
+}
+
+// https://docs.github.com/en/rest/code-scanning/code-scanning#list-code-scanning-alerts-for-a-repository
+
+type codeScanningAlert struct {
+	Number    int    `json:"number"`
+	State     string `json:"state"`
+	HTMLUrl   string `json:"html_url"`
+	CreatedAt string `json:"created_at"`
+	Rule      struct {
+		ID                    string `json:"id"`
+		Name                  string `json:"name"`
+		Description           string `json:"description"`
+		Severity              string `json:"severity"`
+		SecuritySeverityLevel string `json:"security_severity_level"`
+	} `json:"rule"`
+	Tool struct {
+		Name string `json:"name"`
+	} `json:"tool"`
+	MostRecentInstance struct {
+		Ref      string `json:"ref"`
+		Location struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+		} `json:"location"`
+		Message struct {
+			Text string `json:"text"`
+		} `json:"message"`
+	} `json:"most_recent_instance"`
+}
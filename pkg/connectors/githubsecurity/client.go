@@ -0,0 +1,128 @@
+package githubsecurity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// errUnavailable is returned for features which are not enabled for a
+// repository, e.g. Dependabot alerts or code scanning.
+var errUnavailable = errors.New("not available")
+
+// cachedResponse is kept to send conditional requests, which do not count
+// against the rate limit if nothing changed.  It is also served while the
+// rate limit is exhausted.
+type cachedResponse struct {
+	etag string
+	body []byte
+	next string
+}
+
+var nextLinkPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// get a single page from the GitHub API, returning the body and the URL of
+// the next page, if there is one.
+func (c *Connector) get(ctx context.Context, endpoint string) ([]byte, string, error) {
+	c.mu.Lock()
+	cached, isCached := c.cache[endpoint]
+	blockedUntil := c.blockedUntil
+	c.mu.Unlock()
+
+	if time.Now().Before(blockedUntil) {
+		if isCached {
+			return cached.body, cached.next, nil
+		}
+		return nil, "", fmt.Errorf("rate limited until %s", blockedUntil.Format(time.RFC3339))
+	}
+
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if isCached {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+
+	rateLimited := c.updateRateLimit(ctx, res)
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && isCached:
+		return cached.body, cached.next, nil
+	case res.StatusCode == http.StatusOK:
+		b, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, "", err
+		}
+
+		next := ""
+		if m := nextLinkPattern.FindStringSubmatch(res.Header.Get("Link")); m != nil {
+			next = m[1]
+		}
+
+		if etag := res.Header.Get("ETag"); etag != "" {
+			c.mu.Lock()
+			c.cache[endpoint] = cachedResponse{etag: etag, body: b, next: next}
+			c.mu.Unlock()
+		}
+		return b, next, nil
+	case rateLimited && isCached:
+		return cached.body, cached.next, nil
+	case !rateLimited && (res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusNotFound):
+		return nil, "", errUnavailable
+	}
+
+	b, _ := io.ReadAll(res.Body)
+	return nil, "", fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+}
+
+// updateRateLimit blocks further requests if either the primary rate limit
+// is exhausted or GitHub asks to back off via `Retry-After`.
+//
+// see https://docs.github.com/en/rest/using-the-rest-api/rate-limits-for-the-rest-api
+func (c *Connector) updateRateLimit(ctx context.Context, res *http.Response) bool {
+	var until time.Time
+
+	if retryAfter := res.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil {
+			until = time.Now().Add(time.Duration(seconds) * time.Second)
+		}
+	} else if res.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			until = time.Unix(reset, 0)
+		} else {
+			until = time.Now().Add(time.Minute)
+		}
+	}
+
+	if until.IsZero() {
+		return false
+	}
+
+	slog.WarnContext(ctx, "GitHub rate limit reached",
+		slog.String("url", c.config.URL),
+		slog.Time("until", until))
+
+	c.mu.Lock()
+	c.blockedUntil = until
+	c.mu.Unlock()
+
+	return res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusTooManyRequests
+}
@@ -0,0 +1,303 @@
+/*
+Package githubsecurity reports failing GitHub Actions workflow runs on the
+default branch, open Dependabot alerts and open code scanning alerts.
+
+Responses are cached with their ETag, so unchanged data is requested
+conditionally without counting against the rate limit.  While the rate
+limit is exhausted, the cached responses are served.
+*/
+package githubsecurity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	html "html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+
+	mu           sync.Mutex // Protecting the cache and the rate limit
+	cache        map[string]cachedResponse
+	blockedUntil time.Time
+}
+
+type Config struct {
+	common.HTTPConfig
+	Tag   string
+	Repos []string
+	// MinSeverity of Dependabot and code scanning alerts to report, one of
+	// `low`, `medium` (default), `high` or `critical`.
+	MinSeverity string
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.URL == "" {
+		cfg.URL = "https://api.github.com"
+	}
+	if cfg.MinSeverity == "" {
+		cfg.MinSeverity = "medium"
+	}
+
+	return &Connector{
+		config: *cfg,
+		client: cfg.HTTPConfig.Client(),
+		cache:  make(map[string]cachedResponse),
+	}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	var alerts []connectors.Alert
+
+	for _, repo := range c.config.Repos {
+		runs, err := c.collectWorkflowRuns(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, runs...)
+
+		dependabot, err := c.collectDependabotAlerts(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, dependabot...)
+
+		codeScanning, err := c.collectCodeScanningAlerts(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, codeScanning...)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("GitHub Security (%s)", c.config.URL)
+}
+
+// collectWorkflowRuns reports workflows whose latest completed run on the
+// default branch did not succeed.
+func (c *Connector) collectWorkflowRuns(ctx context.Context, repo string) ([]connectors.Alert, error) {
+	var r repository
+	if err := c.getJSON(ctx, c.config.URL+"/repos/"+repo, &r); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"branch":                {r.DefaultBranch},
+		"status":                {"completed"},
+		"exclude_pull_requests": {"true"},
+		"per_page":              {"100"},
+	}
+	var runs workflowRuns
+	if err := c.getJSON(ctx, c.config.URL+"/repos/"+repo+"/actions/runs?"+query.Encode(), &runs); errors.Is(err, errUnavailable) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[int64]bool)
+	var alerts []connectors.Alert
+	for _, run := range runs.WorkflowRuns {
+		if seen[run.WorkflowID] {
+			continue
+		}
+		seen[run.WorkflowID] = true
+
+		switch run.Conclusion {
+		case "failure", "timed_out", "startup_failure":
+		default:
+			continue
+		}
+
+		alert := c.alert(repo, "Workflow", run.HTMLUrl)
+		alert.Labels["Workflow"] = run.Name
+		alert.Labels["Branch"] = run.HeadBranch
+		alert.Labels["Event"] = run.Event
+		alert.Start = parseTime(ctx, run.UpdatedAt)
+		alert.State = connectors.Critical
+		alert.Description = fmt.Sprintf("Workflow %s #%d: %s", run.Name, run.RunNumber, run.Conclusion)
+		alert.Details = fmt.Sprintf("%s, triggered by %s on %s", run.DisplayTitle, run.Actor.Login, run.Event)
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) collectDependabotAlerts(ctx context.Context, repo string) ([]connectors.Alert, error) {
+	found, err := collectAll[dependabotAlert](ctx, c, c.config.URL+"/repos/"+repo+"/dependabot/alerts?state=open&per_page=100")
+	if errors.Is(err, errUnavailable) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, a := range found {
+		severity := a.SecurityVulnerability.Severity
+		if severity == "" {
+			severity = a.SecurityAdvisory.Severity
+		}
+		state, ok := c.fromSeverity(severity)
+		if !ok {
+			continue
+		}
+
+		details := fmt.Sprintf("%s %s in %s, vulnerable: %s",
+			a.Dependency.Package.Ecosystem, a.Dependency.Package.Name, a.Dependency.ManifestPath, a.SecurityVulnerability.VulnerableVersionRange)
+		if patched := a.SecurityVulnerability.FirstPatchedVersion; patched != nil {
+			details += ", patched: " + patched.Identifier
+		}
+
+		alert := c.alert(repo, "Dependabot", a.HTMLUrl)
+		alert.Labels["Severity"] = severity
+		alert.Labels["Package"] = a.Dependency.Package.Name
+		alert.Labels["Advisory"] = a.SecurityAdvisory.GHSAID
+		alert.Start = parseTime(ctx, a.CreatedAt)
+		alert.State = state
+		alert.Description = fmt.Sprintf("Dependabot #%d: %s", a.Number, a.SecurityAdvisory.Summary)
+		alert.Details = details
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) collectCodeScanningAlerts(ctx context.Context, repo string) ([]connectors.Alert, error) {
+	found, err := collectAll[codeScanningAlert](ctx, c, c.config.URL+"/repos/"+repo+"/code-scanning/alerts?state=open&per_page=100")
+	if errors.Is(err, errUnavailable) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, a := range found {
+		severity := a.Rule.SecuritySeverityLevel
+		if severity == "" {
+			severity = ruleSeverities[a.Rule.Severity]
+		}
+		state, ok := c.fromSeverity(severity)
+		if !ok {
+			continue
+		}
+
+		location := a.MostRecentInstance.Location
+		alert := c.alert(repo, "CodeScanning", a.HTMLUrl)
+		alert.Labels["Severity"] = severity
+		alert.Labels["Rule"] = a.Rule.ID
+		alert.Labels["Tool"] = a.Tool.Name
+		alert.Start = parseTime(ctx, a.CreatedAt)
+		alert.State = state
+		alert.Description = fmt.Sprintf("Code scanning #%d: %s", a.Number, a.Rule.Description)
+		alert.Details = fmt.Sprintf("%s:%d: %s", location.Path, location.StartLine, a.MostRecentInstance.Message.Text)
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) alert(repo, kind, link string) connectors.Alert {
+	return connectors.Alert{
+		Labels: map[string]string{
+			"Project": repo,
+			"Source":  c.config.URL,
+			"Type":    kind,
+		},
+		Links: []html.HTML{
+			html.HTML("<a href=\"" + link + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+		},
+	}
+}
+
+var severities = map[string]int{
+	"low":      1,
+	"medium":   2,
+	"moderate": 2,
+	"high":     3,
+	"critical": 4,
+}
+
+// ruleSeverities maps the severity of non-security code scanning rules.
+var ruleSeverities = map[string]string{
+	"note":    "low",
+	"warning": "medium",
+	"error":   "high",
+}
+
+// fromSeverity maps high and critical severities onto critical, lower ones
+// onto warning and skips those below MinSeverity.
+func (c *Connector) fromSeverity(severity string) (connectors.State, bool) {
+	level, ok := severities[severity]
+	if !ok || level < severities[c.config.MinSeverity] {
+		return connectors.OK, false
+	}
+	if level >= severities["high"] {
+		return connectors.Critical, true
+	}
+	return connectors.Warning, true
+}
+
+// collectAll follows the pagination of GitHub.
+func collectAll[T any](ctx context.Context, c *Connector, endpoint string) ([]T, error) {
+	var items []T
+	for page := 0; endpoint != "" && page < 10; page++ {
+		b, next, err := c.get(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageItems []T
+		if err := json.Unmarshal(b, &pageItems); err != nil {
+			slog.ErrorContext(ctx, "Cannot parse",
+				slog.String("url", endpoint),
+				slog.String("data", string(b)),
+				slog.Any("error", err))
+			return nil, err
+		}
+		items = append(items, pageItems...)
+		endpoint = next
+	}
+
+	return items, nil
+}
+
+func (c *Connector) getJSON(ctx context.Context, endpoint string, v any) error {
+	b, _, err := c.get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", endpoint),
+			slog.String("data", string(b)),
+			slog.Any("error", err))
+		return err
+	}
+	return nil
+}
+
+func parseTime(ctx context.Context, s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse", slog.String("time", s), slog.Any("error", err))
+	}
+	return t
+}
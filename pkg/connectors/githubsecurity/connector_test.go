@@ -0,0 +1,253 @@
+package githubsecurity
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	mockServer := httptest.NewServer(mockGitHub(t, nil, nil))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:   "test",
+		Repos: []string{"example/shop", "example/docs"},
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 4 {
+		t.Fatal("There should be a failed workflow, two Dependabot alerts and one code scanning alert", alerts)
+	}
+
+	if alerts[0].Labels["Type"] != "Workflow" || alerts[0].Labels["Workflow"] != "CI" || alerts[0].State != connectors.Critical {
+		t.Error("The latest failed run of a workflow should be critical", alerts[0])
+	}
+	if alerts[1].Labels["Type"] != "Dependabot" || alerts[1].Labels["Package"] != "lodash" || alerts[1].State != connectors.Critical {
+		t.Error("High severity Dependabot alerts should be critical", alerts[1])
+	}
+	if alerts[2].Labels["Type"] != "Dependabot" || alerts[2].State != connectors.Warning {
+		t.Error("Medium severity Dependabot alerts should be warnings", alerts[2])
+	}
+	if alerts[3].Labels["Type"] != "CodeScanning" || alerts[3].Labels["Rule"] != "go/sql-injection" || alerts[3].State != connectors.Critical {
+		t.Error("Code scanning alerts should be mapped by security severity", alerts[3])
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	var full, notModified atomic.Int32
+	mockServer := httptest.NewServer(mockGitHub(t, &full, &notModified))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:        "test",
+		Repos:      []string{"example/shop"},
+		HTTPConfig: common.HTTPConfig{URL: mockServer.URL},
+	}
+	connector := NewConnector(&cfg)
+
+	first, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fullBefore := full.Load()
+
+	second, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if full.Load() != fullBefore || notModified.Load() == 0 {
+		t.Error("Unchanged data should be requested conditionally", full.Load(), notModified.Load())
+	}
+	if len(first) != len(second) {
+		t.Error("Cached responses should result in the same alerts", first, second)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	var limited atomic.Bool
+	var requests atomic.Int32
+	handler := mockGitHub(t, nil, nil)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		if limited.Load() {
+			res.Header().Set("Retry-After", "60")
+			res.WriteHeader(http.StatusForbidden)
+			_, _ = res.Write([]byte(`{"message": "You have exceeded a secondary rate limit."}`))
+			return
+		}
+		handler.ServeHTTP(res, req)
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:        "test",
+		Repos:      []string{"example/shop"},
+		HTTPConfig: common.HTTPConfig{URL: mockServer.URL},
+	}
+	connector := NewConnector(&cfg)
+
+	if _, err := connector.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	limited.Store(true)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal("Cached responses should be served while rate limited", err)
+	}
+	if len(alerts) != 4 {
+		t.Error("Cached alerts should be shown while rate limited", alerts)
+	}
+
+	before := requests.Load()
+	if _, err := connector.Collect(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if requests.Load() != before {
+		t.Error("No requests should be sent until Retry-After passed")
+	}
+}
+
+// mockGitHub serves example/shop with all features and example/docs
+// without Dependabot and code scanning.
+func mockGitHub(t *testing.T, full, notModified *atomic.Int32) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var body string
+		switch req.URL.Path {
+		case "/repos/example/shop":
+			body = `{"full_name": "example/shop", "default_branch": "main", "html_url": "https://github.com/example/shop"}`
+		case "/repos/example/docs":
+			body = `{"full_name": "example/docs", "default_branch": "master", "html_url": "https://github.com/example/docs"}`
+		case "/repos/example/shop/actions/runs":
+			if req.URL.Query().Get("branch") != "main" {
+				t.Error("Only runs of the default branch should be requested", req.URL)
+			}
+			body = mockRuns
+		case "/repos/example/docs/actions/runs":
+			body = `{"total_count": 0, "workflow_runs": []}`
+		case "/repos/example/shop/dependabot/alerts":
+			if req.URL.Query().Get("after") == "" {
+				res.Header().Set("Link", `<http://`+req.Host+`/repos/example/shop/dependabot/alerts?state=open&per_page=100&after=Y3Vyc29y>; rel="next"`)
+				body = mockDependabotPage1
+			} else {
+				body = mockDependabotPage2
+			}
+		case "/repos/example/shop/code-scanning/alerts":
+			body = mockCodeScanning
+		case "/repos/example/docs/dependabot/alerts":
+			res.WriteHeader(http.StatusForbidden)
+			_, _ = res.Write([]byte(`{"message": "Dependabot alerts are disabled for this repository."}`))
+			return
+		case "/repos/example/docs/code-scanning/alerts":
+			res.WriteHeader(http.StatusNotFound)
+			_, _ = res.Write([]byte(`{"message": "no analysis found"}`))
+			return
+		default:
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		etag := `"` + req.URL.String() + `"`
+		res.Header().Set("ETag", etag)
+		res.Header().Set("X-RateLimit-Remaining", "4999")
+		if req.Header.Get("If-None-Match") == etag {
+			if notModified != nil {
+				notModified.Add(1)
+			}
+			res.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		if full != nil {
+			full.Add(1)
+		}
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	})
+}
+
+const mockRuns = `
+{
+  "total_count": 3,
+  "workflow_runs": [
+    {"id": 30, "name": "CI", "workflow_id": 1, "head_branch": "main", "event": "push", "status": "completed", "conclusion": "failure", "run_number": 112, "html_url": "https://github.com/example/shop/actions/runs/30", "updated_at": "2024-05-02T10:11:12Z", "display_title": "Bump lodash", "actor": {"login": "jo"}},
+    {"id": 29, "name": "Release", "workflow_id": 2, "head_branch": "main", "event": "push", "status": "completed", "conclusion": "success", "run_number": 40, "html_url": "https://github.com/example/shop/actions/runs/29", "updated_at": "2024-05-02T09:00:00Z", "display_title": "Release", "actor": {"login": "jo"}},
+    {"id": 28, "name": "Release", "workflow_id": 2, "head_branch": "main", "event": "push", "status": "completed", "conclusion": "failure", "run_number": 39, "html_url": "https://github.com/example/shop/actions/runs/28", "updated_at": "2024-05-01T09:00:00Z", "display_title": "Release", "actor": {"login": "jo"}}
+  ]
+}
+`
+
+const mockDependabotPage1 = `
+[
+  {
+    "number": 7,
+    "state": "open",
+    "html_url": "https://github.com/example/shop/security/dependabot/7",
+    "created_at": "2024-05-01T08:00:00Z",
+    "dependency": {"package": {"ecosystem": "npm", "name": "lodash"}, "manifest_path": "package-lock.json"},
+    "security_advisory": {"ghsa_id": "GHSA-35jh-r3h4-6jhm", "cve_id": "CVE-2021-23337", "summary": "Command Injection in lodash", "severity": "high"},
+    "security_vulnerability": {"severity": "high", "vulnerable_version_range": "< 4.17.21", "first_patched_version": {"identifier": "4.17.21"}}
+  },
+  {
+    "number": 6,
+    "state": "open",
+    "html_url": "https://github.com/example/shop/security/dependabot/6",
+    "created_at": "2024-05-01T08:00:00Z",
+    "dependency": {"package": {"ecosystem": "npm", "name": "debug"}, "manifest_path": "package-lock.json"},
+    "security_advisory": {"ghsa_id": "GHSA-gxpj-cx7g-858c", "summary": "Regular Expression Denial of Service in debug", "severity": "low"},
+    "security_vulnerability": {"severity": "low", "vulnerable_version_range": "< 2.6.9", "first_patched_version": null}
+  }
+]
+`
+
+const mockDependabotPage2 = `
+[
+  {
+    "number": 5,
+    "state": "open",
+    "html_url": "https://github.com/example/shop/security/dependabot/5",
+    "created_at": "2024-04-01T08:00:00Z",
+    "dependency": {"package": {"ecosystem": "go", "name": "golang.org/x/net"}, "manifest_path": "go.mod"},
+    "security_advisory": {"ghsa_id": "GHSA-4v7x-pqxf-cx7m", "summary": "HTTP/2 rapid reset", "severity": "medium"},
+    "security_vulnerability": {"severity": "medium", "vulnerable_version_range": "< 0.17.0", "first_patched_version": {"identifier": "0.17.0"}}
+  }
+]
+`
+
+const mockCodeScanning = `
+[
+  {
+    "number": 3,
+    "state": "open",
+    "html_url": "https://github.com/example/shop/security/code-scanning/3",
+    "created_at": "2024-05-01T08:00:00Z",
+    "rule": {"id": "go/sql-injection", "name": "go/sql-injection", "description": "Database query built from user-controlled sources", "severity": "error", "security_severity_level": "high"},
+    "tool": {"name": "CodeQL"},
+    "most_recent_instance": {"ref": "refs/heads/main", "location": {"path": "db/query.go", "start_line": 42}, "message": {"text": "This query depends on a user-provided value."}}
+  },
+  {
+    "number": 2,
+    "state": "open",
+    "html_url": "https://github.com/example/shop/security/code-scanning/2",
+    "created_at": "2024-05-01T08:00:00Z",
+    "rule": {"id": "go/unused", "name": "go/unused", "description": "Unused variable", "severity": "note"},
+    "tool": {"name": "CodeQL"},
+    "most_recent_instance": {"ref": "refs/heads/main", "location": {"path": "main.go", "start_line": 7}, "message": {"text": "Unused."}}
+  }
+]
+`
@@ -0,0 +1,66 @@
+package gitlabpipeline
+
+// see https://docs.gitlab.com/ee/api/projects.html
+
+type project struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	DefaultBranch     string `json:"default_branch"`
+	WebUrl            string `json:"web_url"`
+}
+
+// see https://docs.gitlab.com/ee/api/protected_branches.html
+
+type protectedBranch struct {
+	Name string `json:"name"`
+}
+
+// see https://docs.gitlab.com/ee/api/pipelines.html
+
+type pipeline struct {
+	ID        int    `json:"id"`
+	Ref       string `json:"ref"`
+	Sha       string `json:"sha"`
+	Status    string `json:"status"`
+	Source    string `json:"source"`
+	WebUrl    string `json:"web_url"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// see https://docs.gitlab.com/ee/api/jobs.html
+
+type job struct {
+	ID            int      `json:"id"`
+	Name          string   `json:"name"`
+	Stage         string   `json:"stage"`
+	Status        string   `json:"status"`
+	Ref           string   `json:"ref"`
+	FailureReason string   `json:"failure_reason"`
+	WebUrl        string   `json:"web_url"`
+	CreatedAt     string   `json:"created_at"`
+	User          person   `json:"user"`
+	Pipeline      pipeline `json:"pipeline"`
+	TagList       []string `json:"tag_list"`
+}
+
+type person struct {
+	Name string `json:"name"`
+}
+
+// see https://docs.gitlab.com/ee/api/pipeline_schedules.html
+
+type pipelineSchedule struct {
+	ID          int    `json:"id"`
+	Description string `json:"description"`
+	Ref         string `json:"ref"`
+	Cron        string `json:"cron"`
+	NextRunAt   string `json:"next_run_at"`
+	Active      bool   `json:"active"`
+	Owner       person `json:"owner"`
+}
+
+const (
+	statusSuccess = "success"
+	statusFailed  = "failed"
+)
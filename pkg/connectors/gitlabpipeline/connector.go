@@ -0,0 +1,346 @@
+package gitlabpipeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	Tag      string
+	Projects []string
+	Groups   []string
+	// ScheduleDelay is how long an active pipeline schedule may be overdue,
+	// before it is reported.
+	ScheduleDelay time.Duration
+	// PendingTimeout is how long a job may be pending, before it is
+	// reported as stuck.
+	PendingTimeout time.Duration
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.ScheduleDelay == 0 {
+		cfg.ScheduleDelay = time.Hour
+	}
+	if cfg.PendingTimeout == 0 {
+		cfg.PendingTimeout = 30 * time.Minute
+	}
+
+	return &Connector{*cfg, cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	projects, err := c.collectProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, p := range projects {
+		failed, err := c.collectFailedPipelines(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, failed...)
+
+		schedules, err := c.collectStaleSchedules(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, schedules...)
+
+		stuck, err := c.collectStuckJobs(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, stuck...)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("GitLab Pipelines (%s)", c.config.URL)
+}
+
+// collectProjects resolves the configured projects and the projects of the
+// configured groups, or all projects the user is a member of.
+func (c *Connector) collectProjects(ctx context.Context) ([]project, error) {
+	if c.config.Projects == nil && c.config.Groups == nil {
+		return collectAll[project](ctx, c, "/api/v4/projects", map[string]string{"membership": "true", "archived": "false"})
+	}
+
+	var projects []project
+	for _, id := range c.config.Projects {
+		var p project
+		if err := c.getJSON(ctx, "/api/v4/projects/"+url.PathEscape(id), nil, &p); err != nil {
+			return nil, err
+		}
+		projects = append(projects, p)
+	}
+
+	for _, id := range c.config.Groups {
+		groupProjects, err := collectAll[project](ctx, c, "/api/v4/groups/"+url.PathEscape(id)+"/projects", map[string]string{"include_subgroups": "true", "archived": "false"})
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, groupProjects...)
+	}
+
+	return projects, nil
+}
+
+// collectFailedPipelines reports the default and protected branches whose
+// latest finished pipeline failed.
+func (c *Connector) collectFailedPipelines(ctx context.Context, p project) ([]connectors.Alert, error) {
+	protected, err := collectAll[protectedBranch](ctx, c, fmt.Sprintf("/api/v4/projects/%d/protected_branches", p.ID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the most recent pipelines are considered, older ones are
+	// superseded on active branches anyway.
+	var pipelines []pipeline
+	if err := c.getJSON(ctx, fmt.Sprintf("/api/v4/projects/%d/pipelines", p.ID), map[string]string{"order_by": "id", "sort": "desc"}, &pipelines); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var alerts []connectors.Alert
+	for _, pl := range pipelines {
+		if seen[pl.Ref] || !watchedRef(pl.Ref, p.DefaultBranch, protected) {
+			continue
+		}
+		if pl.Status != statusSuccess && pl.Status != statusFailed {
+			continue
+		}
+		seen[pl.Ref] = true
+		if pl.Status != statusFailed {
+			continue
+		}
+
+		var jobs []job
+		if err := c.getJSON(ctx, fmt.Sprintf("/api/v4/projects/%d/pipelines/%d/jobs", p.ID, pl.ID), map[string]string{"scope[]": "failed"}, &jobs); err != nil {
+			return nil, err
+		}
+
+		link, stage, name, details := pl.WebUrl, "", "", ""
+		if len(jobs) > 0 {
+			// Report the earliest failure, later ones are often caused by it
+			j := slices.MinFunc(jobs, func(a, b job) int { return a.ID - b.ID })
+			link, stage, name = j.WebUrl, j.Stage, j.Name
+			details = fmt.Sprintf("Job %s in stage %s failed: %s", j.Name, j.Stage, j.FailureReason)
+		}
+
+		alert := c.alert(p, pl.Ref, stage, "Pipeline", link)
+		alert.Labels["Job"] = name
+		alert.Start = parseTime(ctx, pl.UpdatedAt)
+		alert.State = connectors.Critical
+		alert.Description = fmt.Sprintf("Pipeline #%d failed on %s", pl.ID, pl.Ref)
+		alert.Details = details
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// collectStaleSchedules reports active pipeline schedules which have not
+// been run when they were due.
+func (c *Connector) collectStaleSchedules(ctx context.Context, p project) ([]connectors.Alert, error) {
+	schedules, err := collectAll[pipelineSchedule](ctx, c, fmt.Sprintf("/api/v4/projects/%d/pipeline_schedules", p.ID), map[string]string{"scope": "active"})
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, s := range schedules {
+		next := parseTime(ctx, s.NextRunAt)
+		if !s.Active || next.IsZero() || time.Since(next) < c.config.ScheduleDelay {
+			continue
+		}
+
+		alert := c.alert(p, s.Ref, "", "Schedule", fmt.Sprintf("%s/-/pipeline_schedules", p.WebUrl))
+		alert.Start = next
+		alert.State = connectors.Warning
+		alert.Description = fmt.Sprintf("Schedule %s has not run since %s", s.Description, next.Format(time.DateTime))
+		alert.Details = fmt.Sprintf("Cron: %s, Owner: %s", s.Cron, s.Owner.Name)
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// collectStuckJobs reports jobs which are pending for too long, usually as
+// there is no runner available to pick them up.
+func (c *Connector) collectStuckJobs(ctx context.Context, p project) ([]connectors.Alert, error) {
+	jobs, err := collectAll[job](ctx, c, fmt.Sprintf("/api/v4/projects/%d/jobs", p.ID), map[string]string{"scope[]": "pending"})
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, j := range jobs {
+		created := parseTime(ctx, j.CreatedAt)
+		if time.Since(created) < c.config.PendingTimeout {
+			continue
+		}
+
+		alert := c.alert(p, j.Ref, j.Stage, "Job", j.WebUrl)
+		alert.Labels["Job"] = j.Name
+		alert.Start = created
+		alert.State = connectors.Warning
+		alert.Description = fmt.Sprintf("Job %s stuck pending on %s", j.Name, j.Ref)
+		alert.Details = fmt.Sprintf("Pending since %s, runner tags: %v", created.Format(time.DateTime), j.TagList)
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) alert(p project, ref, stage, kind, link string) connectors.Alert {
+	return connectors.Alert{
+		Labels: map[string]string{
+			"Project": p.PathWithNamespace,
+			"Ref":     ref,
+			"Stage":   stage,
+			"Source":  c.config.URL,
+			"Type":    kind,
+		},
+		Links: []html.HTML{
+			html.HTML("<a href=\"" + link + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+		},
+	}
+}
+
+// watchedRef matches the default branch and the protected branches, which
+// might contain wildcards.
+func watchedRef(ref, defaultBranch string, protected []protectedBranch) bool {
+	if ref == defaultBranch {
+		return true
+	}
+	for _, b := range protected {
+		if matched, _ := path.Match(b.Name, ref); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// collectAll follows the pagination of GitLab.
+func collectAll[T any](ctx context.Context, c *Connector, endpoint string, query map[string]string) ([]T, error) {
+	q := map[string]string{}
+	for k, v := range query {
+		q[k] = v
+	}
+
+	var items []T
+	for {
+		body, next, err := c.get(ctx, endpoint, q)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []T
+		err = json.NewDecoder(body).Decode(&page)
+		body.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "Cannot parse",
+				slog.String("url", c.config.URL+endpoint),
+				slog.Any("error", err),
+			)
+			return nil, err
+		}
+		items = append(items, page...)
+
+		if next == "" {
+			break
+		}
+		q["page"] = next
+	}
+
+	return items, nil
+}
+
+func (c *Connector) getJSON(ctx context.Context, endpoint string, query map[string]string, v any) error {
+	body, _, err := c.get(ctx, endpoint, query)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if err := json.NewDecoder(body).Decode(v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+endpoint),
+			slog.Any("error", err),
+		)
+		return err
+	}
+	return nil
+}
+
+// get a single page from GitLab API.
+//
+// Besides the body it returns the number of the next page, if there is one.
+func (c *Connector) get(ctx context.Context, endpoint string, query map[string]string) (io.ReadCloser, string, error) {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	q := req.URL.Query()
+	q.Set("per_page", strconv.Itoa(100))
+	for k, v := range query {
+		q.Set(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		b, _ := io.ReadAll(res.Body)
+		return nil, "", fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+	}
+
+	return res.Body, res.Header.Get("x-next-page"), nil
+}
+
+func parseTime(ctx context.Context, s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse", slog.String("time", s), slog.Any("error", err))
+	}
+	return t
+}
@@ -0,0 +1,132 @@
+package gitlabpipeline
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	overdue := time.Now().Add(-3 * time.Hour).UTC().Format(time.RFC3339)
+	upcoming := time.Now().Add(3 * time.Hour).UTC().Format(time.RFC3339)
+	pending := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+	recent := time.Now().Add(-time.Minute).UTC().Format(time.RFC3339)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var body string
+		switch req.URL.Path {
+		case "/api/v4/projects/infra/deploy":
+			body = `{"id": 1, "path_with_namespace": "infra/deploy", "default_branch": "main", "web_url": "https://gitlab.example.com/infra/deploy"}`
+		case "/api/v4/groups/shop/projects":
+			if req.URL.Query().Get("page") == "" {
+				res.Header().Set("x-next-page", "2")
+				body = `[]`
+			} else {
+				body = `[{"id": 2, "path_with_namespace": "shop/api", "default_branch": "main", "web_url": "https://gitlab.example.com/shop/api"}]`
+			}
+		case "/api/v4/projects/1/protected_branches":
+			body = `[{"name": "main"}, {"name": "release/*"}]`
+		case "/api/v4/projects/1/pipelines":
+			body = mockPipelines
+		case "/api/v4/projects/1/pipelines/103/jobs":
+			if req.URL.Query().Get("scope[]") != "failed" {
+				t.Error("Only failed jobs should be requested")
+			}
+			body = mockFailedJobs
+		case "/api/v4/projects/1/pipeline_schedules":
+			body = fmt.Sprintf(`[
+				{"id": 1, "description": "nightly", "ref": "main", "cron": "0 1 * * *", "next_run_at": "%s", "active": true, "owner": {"name": "Jo Doe"}},
+				{"id": 2, "description": "weekly", "ref": "main", "cron": "0 1 * * 0", "next_run_at": "%s", "active": true, "owner": {"name": "Jo Doe"}}
+			]`, overdue, upcoming)
+		case "/api/v4/projects/1/jobs":
+			body = fmt.Sprintf(`[
+				{"id": 301, "name": "deploy", "stage": "deploy", "status": "pending", "ref": "main", "web_url": "https://gitlab.example.com/infra/deploy/-/jobs/301", "created_at": "%s", "tag_list": ["arm64"]},
+				{"id": 302, "name": "lint", "stage": "test", "status": "pending", "ref": "main", "web_url": "https://gitlab.example.com/infra/deploy/-/jobs/302", "created_at": "%s", "tag_list": []}
+			]`, pending, recent)
+		case "/api/v4/projects/2/protected_branches", "/api/v4/projects/2/pipelines", "/api/v4/projects/2/pipeline_schedules", "/api/v4/projects/2/jobs":
+			body = `[]`
+		default:
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:      "test",
+		Projects: []string{"infra/deploy"},
+		Groups:   []string{"shop"},
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 3 {
+		t.Fatal("There should be a failed pipeline, a stale schedule and a stuck job", alerts)
+	}
+
+	failed := alerts[0]
+	if failed.Labels["Type"] != "Pipeline" || failed.Labels["Ref"] != "release/1.2" || failed.Labels["Stage"] != "build" || failed.Labels["Project"] != "infra/deploy" {
+		t.Error("Failed pipelines on protected branches should be reported with the first failing job", failed)
+	}
+	if string(failed.Links[0]) != `<a href="https://gitlab.example.com/infra/deploy/-/jobs/201" target="_blank" alt="Home">🏠</a>` {
+		t.Error("Failed pipelines should link to the failed job", failed.Links)
+	}
+	if alerts[1].Labels["Type"] != "Schedule" || alerts[1].State != connectors.Warning {
+		t.Error("Overdue schedules should be reported", alerts[1])
+	}
+	if alerts[2].Labels["Type"] != "Job" || alerts[2].Labels["Job"] != "deploy" {
+		t.Error("Long pending jobs should be reported", alerts[2])
+	}
+}
+
+func TestWatchedRef(t *testing.T) {
+	protected := []protectedBranch{{Name: "stable"}, {Name: "release/*"}}
+
+	for ref, expected := range map[string]bool{
+		"main":        true,
+		"stable":      true,
+		"release/1.2": true,
+		"feature/foo": false,
+	} {
+		if watchedRef(ref, "main", protected) != expected {
+			t.Errorf("%s should be watched: %v", ref, expected)
+		}
+	}
+}
+
+// mockPipelines contains a failed MR pipeline and a failed feature branch
+// pipeline to be ignored, a main branch fixed by a later pipeline, and a
+// failed release branch with a newer pipeline still running.
+const mockPipelines = `
+[
+  {"id": 106, "ref": "release/1.2", "sha": "a", "status": "running", "source": "push", "web_url": "https://gitlab.example.com/infra/deploy/-/pipelines/106", "updated_at": "2024-05-02T10:00:00Z"},
+  {"id": 105, "ref": "refs/merge-requests/7/head", "sha": "b", "status": "failed", "source": "merge_request_event", "web_url": "https://gitlab.example.com/infra/deploy/-/pipelines/105", "updated_at": "2024-05-02T09:50:00Z"},
+  {"id": 104, "ref": "main", "sha": "c", "status": "success", "source": "push", "web_url": "https://gitlab.example.com/infra/deploy/-/pipelines/104", "updated_at": "2024-05-02T09:40:00Z"},
+  {"id": 103, "ref": "release/1.2", "sha": "d", "status": "failed", "source": "push", "web_url": "https://gitlab.example.com/infra/deploy/-/pipelines/103", "updated_at": "2024-05-02T09:30:00Z"},
+  {"id": 102, "ref": "main", "sha": "e", "status": "failed", "source": "push", "web_url": "https://gitlab.example.com/infra/deploy/-/pipelines/102", "updated_at": "2024-05-02T09:20:00Z"},
+  {"id": 101, "ref": "feature/foo", "sha": "f", "status": "failed", "source": "push", "web_url": "https://gitlab.example.com/infra/deploy/-/pipelines/101", "updated_at": "2024-05-02T09:10:00Z"}
+]
+`
+
+const mockFailedJobs = `
+[
+  {"id": 202, "name": "e2e", "stage": "test", "status": "failed", "ref": "release/1.2", "failure_reason": "script_failure", "web_url": "https://gitlab.example.com/infra/deploy/-/jobs/202"},
+  {"id": 201, "name": "compile", "stage": "build", "status": "failed", "ref": "release/1.2", "failure_reason": "script_failure", "web_url": "https://gitlab.example.com/infra/deploy/-/jobs/201"}
+]
+`
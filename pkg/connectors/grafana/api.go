@@ -36,6 +36,24 @@ type alert struct {
 	Value       string            `json:"value"`
 }
 
+// https://github.com/prometheus/alertmanager/blob/main/api/v2/openapi.yaml
+type silence struct {
+	ID       string        `json:"id"`
+	Matchers []matcher     `json:"matchers"`
+	Status   silenceStatus `json:"status"`
+}
+
+type silenceStatus struct {
+	State string `json:"state"`
+}
+
+type matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
 type alertingRuleState = string
 
 const (
@@ -44,6 +62,8 @@ const (
 	alertingStateInactive alertingRuleState = "inactive"
 )
 
+const silenceStateActive = "active"
+
 type alertingState = string
 
 const (
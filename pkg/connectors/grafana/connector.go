@@ -9,6 +9,7 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -43,59 +44,113 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 		return nil, err
 	}
 
-	var alerts []connectors.Alert
+	// Without silences, silenced alerts are shown, which is preferable to
+	// showing no alerts at all.
+	silences, err := c.collectSilences(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Cannot get silences",
+			slog.String("url", c.config.URL),
+			slog.Any("error", err))
+	}
 
+	var alerts []connectors.Alert
 	for _, sourceAlertGroup := range sourceAlertGroups {
-		rule := sourceAlertGroup.Rules[0]
-		switch rule.State {
-		case alertingStateInactive:
-			continue
-		case alertingStatePending:
-			fallthrough
-		case alertingStateFiring:
-			// ok
+		for _, rule := range sourceAlertGroup.Rules {
+			if rule.Type != "alerting" || rule.State == alertingStateInactive {
+				continue
+			}
+
+			for _, sourceAlert := range rule.Alerts {
+				state := grafanaStateToState(sourceAlert.State)
+				if state == connectors.OK {
+					continue
+				} else if silenced(silences, sourceAlert.Labels) {
+					continue
+				}
+
+				alerts = append(alerts, c.alert(sourceAlertGroup, rule, sourceAlert, state))
+			}
 		}
+	}
 
-		if rule.Type != "alerting" {
-			continue
-		}
+	return alerts, nil
+}
 
-		sourceAlert := rule.Alerts[0]
+func (c *Connector) alert(group ruleGroup, rule alertingRule, sourceAlert alert, state connectors.State) connectors.Alert {
+	annotations := make(map[string]string)
+	for k, v := range rule.Annotations {
+		annotations[k] = v
+	}
+	for k, v := range sourceAlert.Annotations {
+		annotations[k] = v
+	}
 
-		state := grafanaStateToState(sourceAlert.State)
-		if state == connectors.OK {
-			continue
-		}
+	ruleUID := sourceAlert.Labels["__alert_rule_uid__"]
+	if ruleUID == "" {
+		ruleUID = rule.Labels["rule_uid"]
+	}
 
-		labels := map[string]string{
-			"Hostname":  sourceAlert.Labels["grafana_folder"],
-			"Folder":    sourceAlert.Labels["grafana_folder"],
-			"Alertname": sourceAlert.Labels["alertname"],
-			"Contacts":  sourceAlert.Labels["__contacts__"],
+	var links []html.HTML
+	for _, annotation := range []string{"runbook", "runbook_url"} {
+		if link, ok := annotations[annotation]; ok {
+			links = append(links, html.HTML("<a href=\""+link+"\" target=\"_blank\" alt=\"Runbook\">📖</a>"))
+			break
 		}
-
-		alert := connectors.Alert{
-			Labels:      labels,
-			Start:       parseTime(sourceAlert.ActiveAt),
-			State:       state,
-			Description: rule.Name,
-			Details:     rule.Annotations["message"],
-			Links: []html.HTML{
-				html.HTML("<a href=\"" + c.config.URL + "/alerting/grafana/" + rule.Labels["rule_uid"] + "/view?tab=instances" + "\" target=\"_blank\" alt=\"Alert\">🏠</a>"),
-				html.HTML("<a href=\"" + c.config.URL + "/d/" + rule.Annotations["__dashboardUid__"] + "\" target=\"_blank\" alt=\"Dashboard\">🏠</a>"),
-			},
+	}
+	if ruleUID != "" {
+		links = append(links, html.HTML("<a href=\""+c.config.URL+"/alerting/grafana/"+url.PathEscape(ruleUID)+"/view?tab=instances\" target=\"_blank\" alt=\"Alert\">🏠</a>"))
+	}
+	if dashboard := annotations["__dashboardUid__"]; dashboard != "" {
+		link := c.config.URL + "/d/" + url.PathEscape(dashboard)
+		if panel := annotations["__panelId__"]; panel != "" {
+			link += "?viewPanel=" + url.QueryEscape(panel)
 		}
+		links = append(links, html.HTML("<a href=\""+link+"\" target=\"_blank\" alt=\"Dashboard\">📈</a>"))
+	}
 
-		alerts = append(alerts, alert)
+	var details []string
+	for _, annotation := range []string{"summary", "description", "message"} {
+		if text, ok := annotations[annotation]; ok {
+			details = append(details, text)
+		}
 	}
 
-	return alerts, nil
+	labels := map[string]string{
+		"Cluster": c.config.Cluster,
+		"Source":  c.config.URL,
+		"Group":   group.Name,
+	}
+	// Internal labels like __contacts__ are only shown in their readable form
+	for k, v := range sourceAlert.Labels {
+		if !strings.HasPrefix(k, "__") {
+			labels[k] = v
+		}
+	}
+	labels["Hostname"] = sourceAlert.Labels["grafana_folder"]
+	labels["Folder"] = sourceAlert.Labels["grafana_folder"]
+	labels["Alertname"] = sourceAlert.Labels["alertname"]
+	labels["Contacts"] = sourceAlert.Labels["__contacts__"]
+
+	return connectors.Alert{
+		Labels:      labels,
+		Start:       parseTime(sourceAlert.ActiveAt),
+		State:       state,
+		Description: rule.Name,
+		Details:     strings.Join(details, "\n"),
+		Links:       links,
+		Silence:     c.createSilencer(sourceAlert.Labels),
+	}
 }
 
+// grafanaStateToState maps the state of an instance, which might be annotated
+// with the reason, e.g. `Alerting (NoData)`.
 func grafanaStateToState(state string) connectors.State {
+	state, _, _ = strings.Cut(state, " ")
 	switch strings.ToLower(state) {
 	case alertingStateAlerting:
 		return connectors.Critical
+	case alertingStatePending:
+		return connectors.Warning
 	case alertingStateNoData:
 		return connectors.Unknown
 	case alertingStateError:
@@ -118,6 +173,11 @@ func (c *Connector) collectAlerts(ctx context.Context) ([]ruleGroup, error) {
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("failed to get rules, status code %d: %s", res.StatusCode, string(b))
+	}
+
 	b, _ := io.ReadAll(res.Body)
 	buf := bytes.NewBuffer(b)
 
@@ -2,11 +2,15 @@ package grafana
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/synyx/tuwat/pkg/connectors"
 	"github.com/synyx/tuwat/pkg/connectors/common"
 )
 
@@ -26,6 +30,65 @@ func TestConnector(t *testing.T) {
 	}
 }
 
+func TestInstances(t *testing.T) {
+	var silence struct {
+		Matchers []matcher `json:"matchers"`
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPost && req.URL.Path == silencesEndpoint:
+			_ = json.NewDecoder(req.Body).Decode(&silence)
+			_, _ = res.Write([]byte(`{"silenceID": "0b1c2d3e-1111-2222-3333-444455556666"}`))
+		case req.URL.Path == silencesEndpoint:
+			_, _ = res.Write([]byte(mockSilences))
+		default:
+			_, _ = res.Write([]byte(mockInstancesResponse))
+		}
+	}))
+	defer mockServer.Close()
+
+	cfg := Config{
+		Tag: "test",
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+	connector := NewConnector(&cfg)
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 {
+		t.Fatal("All firing and pending instances which are not silenced should be shown", alerts)
+	}
+	if alerts[0].Labels["instance"] != "a" || alerts[0].State != connectors.Critical {
+		t.Error("Instances should keep their labels", alerts[0].Labels)
+	}
+	if alerts[1].Labels["instance"] != "c" || alerts[1].State != connectors.Warning {
+		t.Error("Pending instances should be warnings", alerts[1])
+	}
+	if len(alerts[0].Links) != 3 || !strings.Contains(string(alerts[0].Links[2]), "/d/UlpdFLWMz?viewPanel=7") {
+		t.Error("Links should be built from the annotations", alerts[0].Links)
+	}
+	if _, ok := alerts[0].Labels["__contacts__"]; ok || alerts[0].Labels["Contacts"] == "" {
+		t.Error("Internal labels should not be shown", alerts[0].Labels)
+	}
+
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []matcher{
+		{Name: "__alert_rule_uid__", Value: "kbMKlW04z", IsEqual: true},
+		{Name: "alertname", Value: "Disk full", IsEqual: true},
+		{Name: "grafana_folder", Value: "Folder", IsEqual: true},
+		{Name: "instance", Value: "a", IsEqual: true},
+	}
+	if !slices.Equal(silence.Matchers, expected) {
+		t.Error("Silences should match the instance", silence.Matchers)
+	}
+}
+
 func testConnector(endpoints map[string]string) (*Connector, *httptest.Server) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		res.WriteHeader(http.StatusOK)
@@ -118,3 +181,129 @@ const mockResponse = `
   }
 }
 `
+
+const mockInstancesResponse = `
+{
+  "status": "success",
+  "data": {
+    "groups": [
+      {
+        "name": "disks",
+        "file": "Folder",
+        "rules": [
+          {
+            "state": "firing",
+            "name": "Disk full",
+            "annotations": {
+              "runbook_url": "https://runbooks.example.com/disk-full"
+            },
+            "alerts": [
+              {
+                "labels": {
+                  "__alert_rule_uid__": "kbMKlW04z",
+                  "__contacts__": "\"Team\"",
+                  "alertname": "Disk full",
+                  "grafana_folder": "Folder",
+                  "instance": "a"
+                },
+                "annotations": {
+                  "__dashboardUid__": "UlpdFLWMz",
+                  "__panelId__": "7",
+                  "summary": "Disk on a is full"
+                },
+                "state": "Alerting",
+                "activeAt": "2024-08-13T12:41:40+02:00",
+                "value": ""
+              },
+              {
+                "labels": {
+                  "__alert_rule_uid__": "kbMKlW04z",
+                  "__contacts__": "\"Team\"",
+                  "alertname": "Disk full",
+                  "grafana_folder": "Folder",
+                  "instance": "b"
+                },
+                "annotations": {
+                  "__dashboardUid__": "UlpdFLWMz",
+                  "__panelId__": "7",
+                  "summary": "Disk on b is full"
+                },
+                "state": "Alerting",
+                "activeAt": "2024-08-13T12:41:40+02:00",
+                "value": ""
+              },
+              {
+                "labels": {
+                  "__alert_rule_uid__": "kbMKlW04z",
+                  "__contacts__": "\"Team\"",
+                  "alertname": "Disk full",
+                  "grafana_folder": "Folder",
+                  "instance": "c"
+                },
+                "annotations": {
+                  "__dashboardUid__": "UlpdFLWMz",
+                  "__panelId__": "7",
+                  "summary": "Disk on c is full"
+                },
+                "state": "Pending",
+                "activeAt": "2024-08-13T12:41:40+02:00",
+                "value": ""
+              },
+              {
+                "labels": {
+                  "__alert_rule_uid__": "kbMKlW04z",
+                  "__contacts__": "\"Team\"",
+                  "alertname": "Disk full",
+                  "grafana_folder": "Folder",
+                  "instance": "d"
+                },
+                "annotations": {
+                  "__dashboardUid__": "UlpdFLWMz",
+                  "__panelId__": "7",
+                  "summary": "Disk on d is full"
+                },
+                "state": "Normal",
+                "activeAt": "2024-08-13T12:41:40+02:00",
+                "value": ""
+              }
+            ],
+            "labels": {
+              "rule_uid": "kbMKlW04z"
+            },
+            "type": "alerting"
+          },
+          {
+            "state": "inactive",
+            "name": "Disk filling up",
+            "type": "alerting"
+          },
+          {
+            "name": "disk:usage",
+            "type": "recording"
+          }
+        ]
+      }
+    ]
+  }
+}
+`
+
+const mockSilences = `
+[
+  {
+    "id": "7d8c5d7a-4b2e-4c3f-9f0a-8c0e5b1d2a3f",
+    "matchers": [
+      {"name": "alertname", "value": "Disk full", "isRegex": false, "isEqual": true},
+      {"name": "instance", "value": "b|x", "isRegex": true, "isEqual": true}
+    ],
+    "status": {"state": "active"}
+  },
+  {
+    "id": "4e5f6a7b-4b2e-4c3f-9f0a-8c0e5b1d2a3f",
+    "matchers": [
+      {"name": "alertname", "value": "Disk full", "isRegex": false, "isEqual": true}
+    ],
+    "status": {"state": "expired"}
+  }
+]
+`
@@ -0,0 +1,162 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/version"
+)
+
+// silencesEndpoint is the Alertmanager compatible API of the Grafana managed
+// alerts.
+const silencesEndpoint = "/api/alertmanager/grafana/api/v2/silences"
+
+func (c *Connector) createSilencer(labels map[string]string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+
+		return c.Silence(ctx, labels, duration, user)
+	}
+}
+
+// Silence creates a silence matching the labels of the alert instance.
+// Internal labels are skipped, except for the uid of the rule.
+//
+// see https://grafana.com/docs/grafana/latest/alerting/configure-notifications/create-silence/
+func (c *Connector) Silence(ctx context.Context, labels map[string]string, duration time.Duration, user string) error {
+	var ms []matcher
+	for name, value := range labels {
+		if strings.HasPrefix(name, "__") && name != "__alert_rule_uid__" {
+			continue
+		}
+		ms = append(ms, matcher{Name: name, Value: value, IsRegex: false, IsEqual: true})
+	}
+	slices.SortFunc(ms, func(a, b matcher) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	now := time.Now()
+	payload := map[string]interface{}{
+		"matchers":  ms,
+		"startsAt":  now.Format(time.RFC3339),
+		"endsAt":    now.Add(duration).Format(time.RFC3339),
+		"createdBy": user,
+		"comment":   fmt.Sprintf("%s: silenced via %s", user, version.Info.Application),
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL+silencesEndpoint, buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to post %s, status code %d: %s", silencesEndpoint, res.StatusCode, string(b))
+	}
+
+	var response struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return err
+	}
+
+	slog.InfoContext(ctx, "silenced alert",
+		slog.String("alertname", labels["alertname"]),
+		slog.String("silence", response.SilenceID),
+		slog.String("user", user))
+
+	return nil
+}
+
+func (c *Connector) collectSilences(ctx context.Context) ([]silence, error) {
+	res, err := c.get(ctx, silencesEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return nil, fmt.Errorf("failed to get silences, status code %d: %s", res.StatusCode, string(b))
+	}
+
+	var response []silence
+	if err = json.NewDecoder(res.Body).Decode(&response); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+silencesEndpoint),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return nil, err
+	}
+
+	var active []silence
+	for _, s := range response {
+		if s.Status.State == silenceStateActive {
+			active = append(active, s)
+		}
+	}
+
+	return active, nil
+}
+
+// silenced returns whether any of the silences matches the labels, as the
+// rules API does not report silenced instances.
+func silenced(silences []silence, labels map[string]string) bool {
+	for _, s := range silences {
+		if len(s.Matchers) > 0 && s.matches(labels) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s silence) matches(labels map[string]string) bool {
+	for _, m := range s.Matchers {
+		if !m.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m matcher) matches(labels map[string]string) bool {
+	value := labels[m.Name]
+
+	var matched bool
+	if m.IsRegex {
+		r, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return false
+		}
+		matched = r.MatchString(value)
+	} else {
+		matched = value == m.Value
+	}
+
+	return matched == m.IsEqual
+}
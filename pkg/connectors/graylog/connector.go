@@ -11,7 +11,9 @@ import (
 	"net/http"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/synyx/tuwat/pkg/connectors"
@@ -21,17 +23,53 @@ import (
 type Connector struct {
 	config Config
 	client *http.Client
+
+	mu       sync.Mutex // Protecting silenced
+	silenced map[string]time.Time
 }
 
 type Config struct {
 	Tag       string
 	Cluster   string
 	TimeRange int
+	// Streams and EventDefinitions limit the events to the given ids or
+	// titles, if set.
+	Streams          []string
+	EventDefinitions []string
+	// Priorities maps the priorities `Low`, `Normal` and `High` onto `ok`,
+	// `warning`, `critical` or `unknown`, overriding the defaults per
+	// priority.  Priorities are case-insensitive.  Events mapped onto `ok` are
+	// not shown.
+	Priorities map[string]string
 	common.HTTPConfig
 }
 
 func NewConnector(cfg *Config) *Connector {
-	c := &Connector{config: *cfg, client: cfg.HTTPConfig.Client()}
+	priorities := map[string]string{
+		"Low":    "warning",
+		"Normal": "warning",
+		"High":   "critical",
+	}
+	for priority, state := range cfg.Priorities {
+		label := priorityLabel(priority)
+		if label == "" {
+			panic(fmt.Errorf("graylog: unknown priority %q", priority))
+		}
+
+		switch strings.ToLower(state) {
+		case "ok", "warning", "critical", "unknown":
+			priorities[label] = state
+		default:
+			panic(fmt.Errorf("graylog: unknown state %q for priority %q", state, priority))
+		}
+	}
+	cfg.Priorities = priorities
+
+	c := &Connector{
+		config:   *cfg,
+		client:   cfg.HTTPConfig.Client(),
+		silenced: make(map[string]time.Time),
+	}
 
 	return c
 }
@@ -48,20 +86,50 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 
 	hostname, _ := url.Parse(c.config.URL)
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
 	var alerts []connectors.Alert
 	var seenEventDefinitions []string
 
 	for _, sourceAlertPage := range sourceAlertPages {
 		for _, sourceAlert := range sourceAlertPage.Events {
+			definition := sourceAlertPage.Context.EventDefinitions[sourceAlert.Event.EventDefinitionId]
+			if !allowed(c.config.EventDefinitions, sourceAlert.Event.EventDefinitionId, definition["title"]) {
+				continue
+			}
+
+			var streams []string
+			streamAllowed := len(c.config.Streams) == 0
+			for _, id := range sourceAlert.Event.Streams {
+				title := id
+				if stream, ok := sourceAlertPage.Context.Streams[id]; ok {
+					title = stream.Title
+				}
+				streams = append(streams, title)
+				streamAllowed = streamAllowed || allowed(c.config.Streams, id, title)
+			}
+			if !streamAllowed {
+				continue
+			}
+
 			eventAggregationId := eventToAggregationId(sourceAlert)
 			if slices.Contains(seenEventDefinitions, eventAggregationId) {
 				continue
 			}
 			seenEventDefinitions = append(seenEventDefinitions, eventAggregationId)
 
-			var streams []string
-			for _, stream := range sourceAlertPage.Context.Streams {
-				streams = append(streams, stream.Title)
+			if until, ok := c.silenced[eventAggregationId]; ok && now.Before(until) {
+				continue
+			}
+
+			state := connectors.Unknown
+			if mapped, ok := c.config.Priorities[priorityToLabel(sourceAlert.Event.Priority)]; ok {
+				state = parseState(mapped)
+			}
+			if state == connectors.OK {
+				continue
 			}
 
 			labels := map[string]string{
@@ -82,18 +150,20 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 				// structure online, thus we assume it has a description field.
 				fallthrough
 			case eventDefinitionAggregationv1:
-				details = sourceAlertPage.Context.EventDefinitions[sourceAlert.Event.EventDefinitionId]["description"]
+				details = definition["description"]
 			}
 
 			alert := connectors.Alert{
 				Labels:      labels,
 				Start:       parseTime(sourceAlert.Event.TimeStamp),
-				State:       priorityToState(sourceAlert.Event.Priority),
+				State:       state,
 				Description: sourceAlert.Event.Message,
 				Details:     details,
 				Links: []html.HTML{
-					html.HTML("<a href=\"" + c.config.URL + "/alerts" + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+					html.HTML("<a href=\"" + c.config.URL + "/alerts/" + url.PathEscape(sourceAlert.Event.Id) + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+					html.HTML("<a href=\"" + html.HTMLEscapeString(c.searchURL(sourceAlert.Event)) + "\" target=\"_blank\" alt=\"Search\">🔍</a>"),
 				},
+				Silence: c.createSilencer(eventAggregationId),
 			}
 			alerts = append(alerts, alert)
 		}
@@ -106,6 +176,58 @@ func (c *Connector) String() string {
 	return fmt.Sprintf("Graylog (%s)", c.config.URL)
 }
 
+// createSilencer hides the events of the definition and group within tuwat,
+// Graylog itself is not changed.  Silences are lost on restart.
+func (c *Connector) createSilencer(eventAggregationId string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+		slog.InfoContext(ctx, "silencing event", slog.String("event", eventAggregationId), slog.String("user", user))
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		now := time.Now()
+		for k, until := range c.silenced {
+			if now.After(until) {
+				delete(c.silenced, k)
+			}
+		}
+		c.silenced[eventAggregationId] = now.Add(duration)
+
+		return nil
+	}
+}
+
+// searchURL searches the messages, which triggered the event, within the
+// streams and time range of the event.
+func (c *Connector) searchURL(event eventsSearchEventResult) string {
+	var fields []string
+	for name, value := range event.GroupByFields {
+		fields = append(fields, name+":"+strconv.Quote(value))
+	}
+	slices.Sort(fields)
+
+	query := url.Values{
+		"rangetype": {"absolute"},
+		"from":      {event.TimeRangeStart},
+		"to":        {event.TimeRangeEnd},
+	}
+	if len(fields) > 0 {
+		query.Set("q", strings.Join(fields, " AND "))
+	}
+	if len(event.Streams) > 0 {
+		query.Set("streams", strings.Join(event.Streams, ","))
+	}
+
+	return c.config.URL + "/search?" + query.Encode()
+}
+
+// allowed returns whether the id or title is in the allow-list, an empty
+// allow-list allows everything.
+func allowed(allowList []string, id, title string) bool {
+	return len(allowList) == 0 || slices.Contains(allowList, id) || (title != "" && slices.Contains(allowList, title))
+}
+
 func (c *Connector) collectAlertEvents(ctx context.Context) ([]eventsSearchResults, error) {
 	timeRangeSeconds := c.config.TimeRange
 	if timeRangeSeconds == 0 {
@@ -212,17 +334,27 @@ func priorityToLabel(priority int) string {
 	}
 }
 
-func priorityToState(priority int) connectors.State {
-	switch priority {
-	case priorityLow:
-		return connectors.Warning
-	case priorityNormal:
+// priorityLabel normalizes the configured priority, returning an empty string
+// for unknown priorities.
+func priorityLabel(priority string) string {
+	for _, p := range []int{priorityLow, priorityNormal, priorityHigh} {
+		if label := priorityToLabel(p); strings.EqualFold(priority, label) {
+			return label
+		}
+	}
+	return ""
+}
+
+func parseState(state string) connectors.State {
+	switch strings.ToLower(state) {
+	case "ok":
+		return connectors.OK
+	case "warning":
 		return connectors.Warning
-	case priorityHigh:
+	case "critical":
 		return connectors.Critical
-	default:
-		return connectors.Unknown
 	}
+	return connectors.Unknown
 }
 
 func alertToLabel(isAlert bool) string {
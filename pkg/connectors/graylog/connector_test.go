@@ -6,7 +6,9 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/synyx/tuwat/pkg/connectors"
 	"github.com/synyx/tuwat/pkg/connectors/common"
 )
 
@@ -30,6 +32,98 @@ func TestConnector(t *testing.T) {
 	}
 }
 
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		name   string
+		config func(*Config)
+		state  connectors.State
+		alerts int
+	}{
+		{"default", func(cfg *Config) {}, connectors.Warning, 1},
+		{"stream title", func(cfg *Config) { cfg.Streams = []string{"All events"} }, connectors.Warning, 1},
+		{"other stream", func(cfg *Config) { cfg.Streams = []string{"000000000000000000000001"} }, connectors.Warning, 0},
+		{"event definition id", func(cfg *Config) { cfg.EventDefinitions = []string{"62e11a902f23dc2537db9efd"} }, connectors.Warning, 1},
+		{"other event definition", func(cfg *Config) { cfg.EventDefinitions = []string{"Warning(s) occured"} }, connectors.Warning, 0},
+		{"priority", func(cfg *Config) { cfg.Priorities = map[string]string{"Normal": "critical"} }, connectors.Critical, 1},
+		{"lowercase priority", func(cfg *Config) { cfg.Priorities = map[string]string{"normal": "critical"} }, connectors.Critical, 1},
+		{"ignored priority", func(cfg *Config) { cfg.Priorities = map[string]string{"Normal": "ok"} }, connectors.OK, 0},
+		{"other priority", func(cfg *Config) { cfg.Priorities = map[string]string{"High": "unknown"} }, connectors.Warning, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connector, mockServer := testConnector(map[string][]string{
+				"/api/events/search": {mockEventSearchResult, mockEventEmptyResult},
+			})
+			defer mockServer.Close()
+
+			cfg := connector.config
+			cfg.Priorities = nil
+			tt.config(&cfg)
+			connector = NewConnector(&cfg)
+
+			alerts, err := connector.Collect(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(alerts) != tt.alerts {
+				t.Fatalf("There should be %d alerts, got %d", tt.alerts, len(alerts))
+			}
+			if len(alerts) > 0 && alerts[0].State != tt.state {
+				t.Errorf("The state should be %v, got %v", tt.state, alerts[0].State)
+			}
+		})
+	}
+}
+
+func TestInvalidPriorities(t *testing.T) {
+	for _, priorities := range []map[string]string{{"High": "urgent"}, {"Urgent": "critical"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Error("Unknown priorities and states should be rejected", priorities)
+				}
+			}()
+
+			NewConnector(&Config{Priorities: priorities})
+		}()
+	}
+}
+
+func TestSilence(t *testing.T) {
+	connector, mockServer := testConnector(map[string][]string{
+		"/api/events/search": {mockEventSearchResult, mockEventEmptyResult, mockEventSearchResult, mockEventEmptyResult},
+	})
+	defer mockServer.Close()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 {
+		t.Fatal("There should be alerts")
+	}
+	if !strings.Contains(string(alerts[0].Links[0]), "/alerts/01J5TR06DQQEXXQEY7T449GAFJ") ||
+		!strings.Contains(string(alerts[0].Links[1]), "streams=000000000000000000000002") {
+		t.Error("Links should point to the event and its messages", alerts[0].Links)
+	}
+	if alerts[0].Labels["Stream"] != "All events" {
+		t.Error("Streams of the event should be shown", alerts[0].Labels)
+	}
+
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+
+	alerts, err = connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 0 {
+		t.Error("Silenced events should be hidden", alerts)
+	}
+}
+
 // testConnector builds a connector with a mocked backend.
 // Each usage of the backend server will return the next mocked body in order.
 func testConnector(endpoints map[string][]string) (*Connector, *httptest.Server) {
@@ -7,6 +7,7 @@ type HostAttrs struct {
 	Host Host `json:"attrs"`
 }
 type Host struct {
+	Name                string   `json:"name"`
 	DisplayName         string   `json:"display_name"`
 	State               int      `json:"state"`
 	LastStateChange     float64  `json:"last_state_change"`
@@ -27,22 +28,57 @@ type serviceAttrs struct {
 	Service service `json:"attrs"`
 }
 type service struct {
-	HostName            string      `json:"host_name"`
-	Name                string      `json:"name"`
-	DisplayName         string      `json:"display_name"`
-	Zone                string      `json:"zone"`
-	State               int         `json:"state"`
-	LastStateChange     float64     `json:"last_state_change"`
-	Acknowledgement     int         `json:"acknowledgement"`
-	DowntimeDepth       int         `json:"downtime_depth"`
-	EnableNotifications bool        `json:"enable_notifications"`
-	LastCheckResult     checkResult `json:"last_check_result"`
-	MaxCheckAttempts    int         `json:"max_check_attempts"`
-	CheckAttempt        int         `json:"check_attempt"`
-	Groups              []string    `json:"groups"`
-	NotesUrl            string      `json:"notes_url"`
+	HostName            string         `json:"host_name"`
+	Name                string         `json:"name"`
+	DisplayName         string         `json:"display_name"`
+	Zone                string         `json:"zone"`
+	State               int            `json:"state"`
+	LastStateChange     float64        `json:"last_state_change"`
+	Acknowledgement     int            `json:"acknowledgement"`
+	DowntimeDepth       int            `json:"downtime_depth"`
+	EnableNotifications bool           `json:"enable_notifications"`
+	LastCheckResult     checkResult    `json:"last_check_result"`
+	MaxCheckAttempts    int            `json:"max_check_attempts"`
+	CheckAttempt        int            `json:"check_attempt"`
+	Groups              []string       `json:"groups"`
+	NotesUrl            string         `json:"notes_url"`
+	Vars                map[string]any `json:"vars"`
 }
 
 type checkResult struct {
 	Output string `json:"output"`
 }
+
+// https://icinga.com/docs/icinga-2/latest/doc/12-icinga2-api/#event-stream-types
+
+type event struct {
+	Type      string  `json:"type"`
+	Timestamp float64 `json:"timestamp"`
+	Host      string  `json:"host"`
+	Service   string  `json:"service"`
+	State     int     `json:"state"`
+
+	// StateChange
+	CheckResult     *checkResult `json:"check_result"`
+	DowntimeDepth   *int         `json:"downtime_depth"`
+	Acknowledgement *bool        `json:"acknowledgement"`
+
+	// AcknowledgementSet
+	AcknowledgementType int `json:"acknowledgement_type"`
+
+	// DowntimeStarted, DowntimeRemoved
+	Downtime *downtime `json:"downtime"`
+}
+
+type downtime struct {
+	HostName    string `json:"host_name"`
+	ServiceName string `json:"service_name"`
+}
+
+const (
+	eventStateChange            = "StateChange"
+	eventAcknowledgementSet     = "AcknowledgementSet"
+	eventAcknowledgementCleared = "AcknowledgementCleared"
+	eventDowntimeStarted        = "DowntimeStarted"
+	eventDowntimeRemoved        = "DowntimeRemoved"
+)
@@ -1,6 +1,7 @@
 package icinga2
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	"log/slog"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,16 +21,54 @@ import (
 type Connector struct {
 	config Config
 	client *http.Client
+
+	state objectState
 }
 
 type Config struct {
 	Tag          string
 	DashboardURL string
+	// Stream keeps the state of all objects locally, updated via the event
+	// stream instead of polling all objects on every collection.
+	Stream bool
+	// ResyncInterval is how often all objects are fetched again in stream
+	// mode, to make up for missed events.
+	ResyncInterval time.Duration
+	// HostFilter and ServiceFilter are filter expressions, e.g.
+	// `match("prod-*", host.name)`, evaluated by Icinga.  The HostFilter
+	// applies to services as well.
+	HostFilter    string
+	ServiceFilter string
+	// HostGroups limits to hosts in any of the groups, ExcludeHostGroups
+	// excludes hosts in any of the groups.
+	HostGroups        []string
+	ExcludeHostGroups []string
+	// SilenceMode is either `acknowledgement` or `downtime`.
+	SilenceMode string
+	// ShowSilenced shows acknowledged problems and problems in downtime, to be
+	// able to unsilence them.
+	ShowSilenced bool
 	common.HTTPConfig
 }
 
+const (
+	silenceAcknowledgement = "acknowledgement"
+	silenceDowntime        = "downtime"
+)
+
 func NewConnector(cfg *Config) *Connector {
-	return &Connector{*cfg, cfg.HTTPConfig.Client()}
+	if cfg.ResyncInterval == 0 {
+		cfg.ResyncInterval = 10 * time.Minute
+	}
+	switch cfg.SilenceMode {
+	case "":
+		cfg.SilenceMode = silenceAcknowledgement
+	case silenceAcknowledgement, silenceDowntime:
+	default:
+		panic(fmt.Errorf("icinga2: unknown silence mode %q", cfg.SilenceMode))
+	}
+
+	return &Connector{config: *cfg, client: cfg.HTTPConfig.Client()}
 }
 
 func (c *Connector) Tag() string {
@@ -36,6 +76,14 @@ func (c *Connector) Tag() string {
 }
 
 func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	if c.config.Stream {
+		hosts, services, err := c.streamedObjects(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return c.alerts(hosts, services), nil
+	}
+
 	hosts, err := c.collectHosts(ctx)
 	if err != nil {
 		return nil, err
@@ -45,6 +93,10 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 		return nil, err
 	}
 
+	return c.alerts(hosts, services), nil
+}
+
+func (c *Connector) alerts(hosts map[string]HostAttrs, services []serviceAttrs) []connectors.Alert {
 	var alerts []connectors.Alert
 	ignoredHosts := make(map[string]bool)
 
@@ -52,15 +104,15 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 		host := host.Host
 		ignoredHosts[host.DisplayName] = false
 
-		if host.Acknowledgement > 0 {
+		silenced := silencedBy(host.Acknowledgement, host.DowntimeDepth)
+		if !host.EnableNotifications {
 			ignoredHosts[host.DisplayName] = true
 			continue
-		} else if !host.EnableNotifications {
+		} else if silenced != "" {
 			ignoredHosts[host.DisplayName] = true
-			continue
-		} else if host.DowntimeDepth > 0 {
-			ignoredHosts[host.DisplayName] = true
-			continue
+			if !c.config.ShowSilenced || host.State == 0 {
+				continue
+			}
 		} else if host.State == 0 {
 			continue
 		}
@@ -78,6 +130,7 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 				"Source":   c.config.URL,
 				"groups":   strings.Join(host.Groups, ","),
 				"Type":     "Host",
+				"Silenced": silenced,
 			},
 			Start:       time.Unix(int64(sec), int64(dec*(1e9))),
 			State:       fromHostState(host.State),
@@ -85,19 +138,22 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 			Details:     host.Output,
 			Links:       links,
 		}
-		alert.Silence = c.createSilencer(alert)
+		if silenced == "" {
+			alert.Silence = c.createSilencer("Host", host.Name, "")
+		} else {
+			alert.Unsilence = c.createUnsilencer("Host", host.Name, "", host.Acknowledgement, host.DowntimeDepth)
+		}
 		alerts = append(alerts, alert)
 	}
 
 	for _, service := range services {
 		service := service.Service
+		silenced := silencedBy(service.Acknowledgement, service.DowntimeDepth)
 		if ignore, ok := ignoredHosts[service.HostName]; ok && ignore {
 			continue
-		} else if service.Acknowledgement > 0 {
-			continue
 		} else if !service.EnableNotifications {
 			continue
-		} else if service.DowntimeDepth > 0 {
+		} else if silenced != "" && !c.config.ShowSilenced {
 			continue
 		} else if service.State == 0 {
 			continue
@@ -114,36 +170,90 @@ func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
 		}
 		links = append(links, html.HTML("<a href=\""+c.config.DashboardURL+"/dashboard#!/monitoring/host/show?host="+service.HostName+"&service="+service.Name+"\" target=\"_blank\" alt=\"Home\">🏠</a>"))
 
+		labels := map[string]string{
+			"Hostname":   service.HostName,
+			"Zone":       service.Zone,
+			"Source":     c.config.URL,
+			"groups":     strings.Join(service.Groups, ","),
+			"hostgroups": strings.Join(hostgroups, ","),
+			"Type":       "Service",
+			"Silenced":   silenced,
+		}
+		// Custom variables are exported as far as they are plain values
+		for k, v := range service.Vars {
+			if _, ok := labels[k]; ok {
+				continue
+			}
+			switch v := v.(type) {
+			case string:
+				labels[k] = v
+			case float64:
+				labels[k] = strconv.FormatFloat(v, 'f', -1, 64)
+			case bool:
+				labels[k] = strconv.FormatBool(v)
+			}
+		}
+
 		sec, dec := math.Modf(service.LastStateChange)
 		alert := connectors.Alert{
-			Labels: map[string]string{
-				"Hostname":   service.HostName,
-				"Zone":       service.Zone,
-				"Source":     c.config.URL,
-				"groups":     strings.Join(service.Groups, ","),
-				"hostgroups": strings.Join(hostgroups, ","),
-				"Type":       "Service",
-			},
+			Labels:      labels,
 			Start:       time.Unix(int64(sec), int64(dec*(1e9))),
 			State:       fromServiceState(service.State),
 			Description: service.DisplayName,
 			Details:     service.LastCheckResult.Output,
 			Links:       links,
 		}
-
-		alert.Silence = c.createSilencer(alert)
+		if silenced == "" {
+			alert.Silence = c.createSilencer("Service", service.HostName, service.Name)
+		} else {
+			alert.Unsilence = c.createUnsilencer("Service", service.HostName, service.Name, service.Acknowledgement, service.DowntimeDepth)
+		}
 		alerts = append(alerts, alert)
 	}
 
-	return alerts, nil
+	return alerts
 }
 
 func (c *Connector) String() string {
 	return fmt.Sprintf("Icinga2 (%s)", c.config.URL)
 }
 
+// silencedBy returns how a problem is silenced, if at all.
+func silencedBy(acknowledgement, downtimeDepth int) string {
+	if acknowledgement > 0 {
+		return silenceAcknowledgement
+	} else if downtimeDepth > 0 {
+		return silenceDowntime
+	}
+	return ""
+}
+
+// filter combines the configured filters for hosts or services, to be
+// evaluated by Icinga.
+func (c *Connector) filter(services bool) string {
+	var parts []string
+	if c.config.HostFilter != "" {
+		parts = append(parts, "("+c.config.HostFilter+")")
+	}
+	if services && c.config.ServiceFilter != "" {
+		parts = append(parts, "("+c.config.ServiceFilter+")")
+	}
+	if len(c.config.HostGroups) > 0 {
+		var groups []string
+		for _, g := range c.config.HostGroups {
+			groups = append(groups, strconv.Quote(g)+" in host.groups")
+		}
+		parts = append(parts, "("+strings.Join(groups, " || ")+")")
+	}
+	for _, g := range c.config.ExcludeHostGroups {
+		parts = append(parts, "!("+strconv.Quote(g)+" in host.groups)")
+	}
+
+	return strings.Join(parts, " && ")
+}
+
 func (c *Connector) collectServices(ctx context.Context) ([]serviceAttrs, error) {
-	body, err := c.get("/v1/objects/services", ctx)
+	body, err := c.get(ctx, "/v1/objects/services", c.filter(true))
 	if err != nil {
 		return nil, err
 	}
@@ -161,7 +271,7 @@ func (c *Connector) collectServices(ctx context.Context) ([]serviceAttrs, error)
 }
 
 func (c *Connector) collectHosts(ctx context.Context) (map[string]HostAttrs, error) {
-	body, err := c.get("/v1/objects/hosts", ctx)
+	body, err := c.get(ctx, "/v1/objects/hosts", c.filter(false))
 	if err != nil {
 		return nil, err
 	}
@@ -183,10 +293,28 @@ func (c *Connector) collectHosts(ctx context.Context) (map[string]HostAttrs, err
 	return results, nil
 }
 
-func (c *Connector) get(endpoint string, ctx context.Context) (io.ReadCloser, error) {
-	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+// get queries the objects, filters are sent in the body of a POST request
+// overriding the method, as they might exceed the maximum URL length.
+//
+// see https://icinga.com/docs/icinga-2/latest/doc/12-icinga2-api/#advanced-filters
+func (c *Connector) get(ctx context.Context, endpoint, filter string) (io.ReadCloser, error) {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint), slog.String("filter", filter))
+
+	var req *http.Request
+	var err error
+	if filter == "" {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	} else {
+		buf := new(bytes.Buffer)
+		if err := json.NewEncoder(buf).Encode(map[string]string{"filter": filter}); err != nil {
+			return nil, err
+		}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL+endpoint, buf)
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-HTTP-Method-Override", http.MethodGet)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
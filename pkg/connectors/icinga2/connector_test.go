@@ -2,10 +2,15 @@ package icinga2
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/synyx/tuwat/pkg/connectors"
 	"github.com/synyx/tuwat/pkg/connectors/common"
@@ -58,6 +63,357 @@ func TestIcinga2AckPropagation(t *testing.T) {
 	}
 }
 
+func TestIcinga2FiltersAndSilencing(t *testing.T) {
+	var mu sync.Mutex
+	filters := make(map[string]string)
+	var actions []string
+	var downtime, removed map[string]any
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var body map[string]any
+		_ = json.NewDecoder(req.Body).Decode(&body)
+
+		switch req.URL.Path {
+		case "/v1/objects/hosts":
+			if req.Header.Get("X-HTTP-Method-Override") != http.MethodGet {
+				t.Error("Filtered objects should be queried via method override", req.Header)
+			}
+			filters["hosts"], _ = body["filter"].(string)
+			_, _ = res.Write([]byte(icinga2MockHostResponse))
+		case "/v1/objects/services":
+			filters["services"], _ = body["filter"].(string)
+			_, _ = res.Write([]byte(strings.ReplaceAll(icinga2MockServiceResponse, `"downtime_depth": 0`, `"downtime_depth": 1`)))
+		case "/v1/actions/schedule-downtime":
+			downtime = body
+			actions = append(actions, req.URL.Path)
+			_, _ = res.Write([]byte(`{"results": [{"code": 200, "status": "Successfully scheduled downtime"}]}`))
+		case "/v1/actions/remove-downtime", "/v1/actions/remove-acknowledgement":
+			removed = body
+			actions = append(actions, req.URL.Path)
+			_, _ = res.Write([]byte(`{"results": []}`))
+		default:
+			res.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:               "test",
+		HostFilter:        `match("*.example.com", host.name)`,
+		ServiceFilter:     `service.vars.team == "ops"`,
+		HostGroups:        []string{"linux", "windows"},
+		ExcludeHostGroups: []string{"lab"},
+		SilenceMode:       "downtime",
+		ShowSilenced:      true,
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+	connector := NewConnector(&cfg)
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if filters["hosts"] != `(match("*.example.com", host.name)) && ("linux" in host.groups || "windows" in host.groups) && !("lab" in host.groups)` {
+		t.Error("Hosts should be filtered by host filter and groups", filters["hosts"])
+	}
+	if filters["services"] != `(match("*.example.com", host.name)) && (service.vars.team == "ops") && ("linux" in host.groups || "windows" in host.groups) && !("lab" in host.groups)` {
+		t.Error("Services should be filtered by service filter as well", filters["services"])
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("Services in downtime should be shown", alerts)
+	}
+	for _, alert := range alerts {
+		if alert.Labels["Silenced"] != "downtime" || alert.Silence != nil || alert.Unsilence == nil {
+			t.Error("Services in downtime should be unsilenceable", alert)
+		}
+		if alert.Description == "Mailq length" && alert.Labels["mailq_critical"] != "7" {
+			t.Error("Service vars should be labels", alert.Labels)
+		}
+	}
+
+	if err := alerts[0].Unsilence(context.Background(), "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The mock host is up, thus silence it directly
+	silence := connector.createSilencer("Host", "test-host.example.com", "")
+	if err := silence(context.Background(), time.Hour, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !slices.Equal(actions, []string{"/v1/actions/remove-downtime", "/v1/actions/schedule-downtime"}) {
+		t.Error("Downtimes should be removed and scheduled", actions)
+	}
+	vars, _ := removed["filter_vars"].(map[string]any)
+	if removed["type"] != "Downtime" || !strings.Contains(removed["filter"].(string), "match(m, downtime.comment)") || vars["m"] != "*: "+silenceComment() {
+		t.Error("Only downtimes created via tuwat should be removed", removed)
+	}
+	if downtime["type"] != "Host" || downtime["all_services"] != true || downtime["filter"] != "host.name==h" {
+		t.Error("Host downtimes should include all services", downtime)
+	}
+}
+
+func TestIcinga2UnsilenceAcknowledgement(t *testing.T) {
+	var mu sync.Mutex
+	var filter string
+	var comments string
+	var actions []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var body map[string]any
+		_ = json.NewDecoder(req.Body).Decode(&body)
+
+		switch req.URL.Path {
+		case "/v1/objects/comments":
+			filter, _ = body["filter"].(string)
+			_, _ = res.Write([]byte(comments))
+		case "/v1/actions/remove-acknowledgement":
+			actions = append(actions, req.URL.Path)
+			_, _ = res.Write([]byte(`{"results": []}`))
+		default:
+			res.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag: "test",
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+	connector := NewConnector(&cfg)
+
+	// Acknowledged by someone else
+	comments = `{"results": []}`
+	if err := connector.Unsilence(context.Background(), "Service", "test-host", "Mailq length", true, false, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 0 {
+		t.Error("Acknowledgements by others should be kept", actions)
+	}
+	if !strings.Contains(filter, `comment.service_name=="Mailq length"`) || !strings.Contains(filter, "comment.entry_type==4") {
+		t.Error("The acknowledgement comment of the service should be looked up", filter)
+	}
+
+	comments = `{"results": [{"attrs": {"author": "jdoe", "text": "jdoe: ` + silenceComment() + `"}}]}`
+	if err := connector.Unsilence(context.Background(), "Service", "test-host", "Mailq length", true, false, "jane"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(actions, []string{"/v1/actions/remove-acknowledgement"}) {
+		t.Error("Acknowledgements created via tuwat should be removed", actions)
+	}
+}
+
+func TestIcinga2Stream(t *testing.T) {
+	var syncs atomic.Int32
+	send := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/objects/hosts":
+			syncs.Add(1)
+			_, _ = res.Write([]byte(icinga2MockHostResponse))
+		case "/v1/objects/services":
+			_, _ = res.Write([]byte(icinga2MockServiceResponse))
+		case "/v1/events":
+			if req.Method != http.MethodPost {
+				t.Error("Events should be subscribed via POST", req.Method)
+			}
+			res.WriteHeader(http.StatusOK)
+			res.(http.Flusher).Flush()
+
+			select {
+			case <-send:
+			case <-req.Context().Done():
+				return
+			}
+			_, _ = res.Write([]byte(icinga2MockEvents))
+			res.(http.Flusher).Flush()
+			<-req.Context().Done()
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:    "test",
+		Stream: true,
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+	connector := NewConnector(&cfg)
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 2 || syncs.Load() != 1 {
+		t.Fatal("The initial collection should sync all objects", alerts)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changed := make(chan bool, 10)
+	go connector.Stream(ctx, func() { changed <- true })
+
+	// Connecting forces a resync, as events might have been missed
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for connection")
+	}
+	if _, err := connector.Collect(ctx); err != nil || syncs.Load() != 2 {
+		t.Fatal("A new connection should resync", err, syncs.Load())
+	}
+
+	close(send)
+	for range 2 {
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for events")
+		}
+	}
+
+	alerts, err = connector.Collect(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if syncs.Load() != 2 {
+		t.Error("Events should not cause a resync", syncs.Load())
+	}
+	if len(alerts) != 2 {
+		t.Fatal("The host should be down and mailq acknowledged", alerts)
+	}
+	for _, alert := range alerts {
+		if alert.Description == "Mailq length" {
+			t.Error("Acknowledged services should not be shown", alert)
+		}
+		if alert.Labels["Type"] == "Host" && (alert.Details != "PING CRITICAL - Packet loss = 100%" || alert.Start.Unix() != 1714644672) {
+			t.Error("The host state should be updated from the event", alert)
+		}
+	}
+}
+
+func TestIcinga2StreamFailure(t *testing.T) {
+	var syncs atomic.Int32
+	disconnect := make(chan struct{})
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v1/objects/hosts":
+			syncs.Add(1)
+			_, _ = res.Write([]byte(icinga2MockHostResponse))
+		case "/v1/objects/services":
+			_, _ = res.Write([]byte(icinga2MockServiceResponse))
+		case "/v1/events":
+			select {
+			case <-disconnect:
+				res.WriteHeader(http.StatusServiceUnavailable)
+				return
+			default:
+			}
+			res.WriteHeader(http.StatusOK)
+			res.(http.Flusher).Flush()
+
+			select {
+			case <-disconnect:
+			case <-req.Context().Done():
+			}
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:    "test",
+		Stream: true,
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+	connector := NewConnector(&cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changed := make(chan bool, 10)
+	go connector.Stream(ctx, func() { changed <- true })
+
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for connection")
+	}
+	for range 2 {
+		if _, err := connector.Collect(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if syncs.Load() != 1 {
+		t.Fatal("A connected stream should not resync", syncs.Load())
+	}
+
+	close(disconnect)
+	select {
+	case <-changed:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the stream to fail")
+	}
+	for range 2 {
+		if _, err := connector.Collect(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if syncs.Load() != 3 {
+		t.Error("Without a connected stream, every collection should resync", syncs.Load())
+	}
+}
+
+func TestIcinga2StreamUnavailable(t *testing.T) {
+	attempts := make(chan bool, 10)
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		attempts <- true
+		res.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:    "test",
+		Stream: true,
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+	connector := NewConnector(&cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	changed := make(chan bool, 10)
+	go connector.Stream(ctx, func() { changed <- true })
+
+	select {
+	case <-attempts:
+	case <-ctx.Done():
+		t.Fatal("timeout waiting for the connection attempt")
+	}
+	select {
+	case <-changed:
+		t.Error("A stream, which never connected, should not signal changes")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
 func testConnector(hostJson, serviceJson string) (connectors.Connector, *httptest.Server) {
 	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
 		res.WriteHeader(http.StatusOK)
@@ -487,3 +843,7 @@ const icinga2MockServiceResponse = `
   ]
 }
 `
+
+const icinga2MockEvents = `{"type": "StateChange", "timestamp": 1714644672.0, "host": "test-host.example.com", "state": 1, "state_type": 1, "check_result": {"output": "PING CRITICAL - Packet loss = 100%"}, "downtime_depth": 0, "acknowledgement": false}
+{"type": "AcknowledgementSet", "timestamp": 1714644680.0, "host": "test-host.example.com", "service": "puppet-mailq", "state": 2, "state_type": 1, "author": "jdoe", "comment": "on it", "acknowledgement_type": 1, "notify": true, "expiry": 0}
+`
@@ -5,42 +5,150 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/synyx/tuwat/pkg/connectors"
 	"github.com/synyx/tuwat/pkg/version"
 )
 
-func (c *Connector) createSilencer(alert connectors.Alert) connectors.SilencerFunc {
+func (c *Connector) createSilencer(objectType, host, service string) connectors.SilencerFunc {
 
 	return func(ctx context.Context, duration time.Duration, user string) error {
 
-		return c.Silence(ctx, alert, duration, user)
+		return c.Silence(ctx, objectType, host, service, duration, user)
 	}
 }
 
-func (c *Connector) Silence(ctx context.Context, alert connectors.Alert, duration time.Duration, user string) error {
+func (c *Connector) createUnsilencer(objectType, host, service string, acknowledgement, downtimeDepth int) connectors.UnsilencerFunc {
+
+	return func(ctx context.Context, user string) error {
+
+		return c.Unsilence(ctx, objectType, host, service, acknowledgement > 0, downtimeDepth > 0, user)
+	}
+}
+
+// Silence acknowledges the problem or schedules a downtime, depending on the
+// configured silence mode.
+//
+// see https://icinga.com/docs/icinga-2/latest/doc/12-icinga2-api/#acknowledge-problem
+// see https://icinga.com/docs/icinga-2/latest/doc/12-icinga2-api/#schedule-downtime
+func (c *Connector) Silence(ctx context.Context, objectType, host, service string, duration time.Duration, user string) error {
+	now := time.Now()
+	payload := objectPayload(objectType, host, service)
+	payload["author"] = user
+	payload["comment"] = fmt.Sprintf("%s: %s", user, silenceComment())
+
+	var endpoint string
+	switch c.config.SilenceMode {
+	case silenceDowntime:
+		endpoint = "/v1/actions/schedule-downtime"
+		payload["start_time"] = now.Unix()
+		payload["end_time"] = now.Add(duration).Unix()
+		payload["fixed"] = true
+		if objectType == "Host" {
+			payload["all_services"] = true
+		}
+	default:
+		endpoint = "/v1/actions/acknowledge-problem"
+		payload["child_options"] = 1
+		payload["start_time"] = now.Unix()
+		payload["expiry"] = now.Add(duration).Unix()
+	}
+
+	return c.post(ctx, endpoint, payload)
+}
+
+// Unsilence removes the acknowledgement and downtimes of the object, which
+// were created via Silence.  Downtimes of hosts include the downtimes of their
+// services.  Acknowledgements and downtimes by others, e.g. for a maintenance,
+// are kept.
+//
+// see https://icinga.com/docs/icinga-2/latest/doc/12-icinga2-api/#remove-acknowledgement
+// see https://icinga.com/docs/icinga-2/latest/doc/12-icinga2-api/#remove-downtime
+func (c *Connector) Unsilence(ctx context.Context, objectType, host, service string, acknowledged, inDowntime bool, user string) error {
+	if acknowledged {
+		owned, err := c.ownAcknowledgement(ctx, host, service)
+		if err != nil {
+			return err
+		}
+		if owned {
+			payload := objectPayload(objectType, host, service)
+			payload["author"] = user
+			if err := c.post(ctx, "/v1/actions/remove-acknowledgement", payload); err != nil {
+				return err
+			}
+		}
+	}
+
+	if inDowntime {
+		payload := map[string]interface{}{
+			"type":   "Downtime",
+			"filter": `downtime.host_name==h && match(m, downtime.comment)`,
+			"author": user,
+		}
+		vars := map[string]string{"h": host, "m": "*: " + silenceComment()}
+		if objectType == "Service" {
+			payload["filter"] = `downtime.host_name==h && downtime.service_name==s && match(m, downtime.comment)`
+			vars["s"] = service
+		}
+		payload["filter_vars"] = vars
+		if err := c.post(ctx, "/v1/actions/remove-downtime", payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// silenceComment marks acknowledgements and downtimes created via tuwat.
+func silenceComment() string {
+	return "silenced via " + version.Info.Application
+}
+
+// ownAcknowledgement returns whether the acknowledgement of the object was
+// created via Silence, as recorded by its comment.
+//
+// see https://icinga.com/docs/icinga-2/latest/doc/09-object-types/#comment
+func (c *Connector) ownAcknowledgement(ctx context.Context, host, service string) (bool, error) {
+	filter := fmt.Sprintf(`comment.entry_type==4 && comment.host_name==%s && comment.service_name==%s && match(%s, comment.text)`,
+		strconv.Quote(host), strconv.Quote(service), strconv.Quote("*: "+silenceComment()))
+
+	body, err := c.get(ctx, "/v1/objects/comments", filter)
+	if err != nil {
+		return false, err
+	}
+	defer body.Close()
+
+	var response struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if err := json.NewDecoder(body).Decode(&response); err != nil {
+		return false, err
+	}
+
+	return len(response.Results) > 0, nil
+}
+
+// objectPayload selects a single host or service, passing the names as
+// variables to not having to escape them within the filter.
+func objectPayload(objectType, host, service string) map[string]interface{} {
 	payload := map[string]interface{}{
-		"type":          alert.Labels["type"],
-		"comment":       fmt.Sprintf("%s: silenced via %s", user, version.Info.Application),
-		"author":        user,
-		"child_options": 1,
-		"start_time":    time.Now().Unix(),
-		"expiry":        time.Now().Add(duration).Unix(),
+		"type": objectType,
 	}
-	endpoint := "/v1/actions/acknowledge-problem"
 
-	switch alert.Labels["type"] {
+	switch objectType {
 	case "Service":
-		payload["filter"] = `host.name=="` + alert.Labels["Hostname"] + `" && service.name=="` + alert.Details + `"`
+		payload["filter"] = `host.name==h && service.name==s`
+		payload["filter_vars"] = map[string]string{"h": host, "s": service}
 	case "Host":
-		payload["filter"] = `host.name=="` + alert.Labels["Hostname"] + `"`
+		payload["filter"] = `host.name==h`
+		payload["filter_vars"] = map[string]string{"h": host}
 	}
 
-	err := c.post(ctx, endpoint, payload)
-
-	return err
+	return payload
 }
 
 func (c *Connector) post(ctx context.Context, endpoint string, content map[string]interface{}) error {
@@ -56,13 +164,19 @@ func (c *Connector) post(ctx context.Context, endpoint string, content map[strin
 		return err
 	}
 
+	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := http.DefaultClient.Do(req)
+	res, err := c.client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer res.Body.Close()
 
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to post %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+	}
+
 	return nil
 }
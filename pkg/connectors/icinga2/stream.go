@@ -0,0 +1,343 @@
+package icinga2
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+const (
+	// streamRetryDelay is the time to wait before reconnecting a failed
+	// stream, doubled on every further failure up to streamMaxRetryDelay.
+	streamRetryDelay    = 10 * time.Second
+	streamMaxRetryDelay = 5 * time.Minute
+)
+
+var streamTypes = []string{
+	eventStateChange,
+	eventAcknowledgementSet,
+	eventAcknowledgementCleared,
+	eventDowntimeStarted,
+	eventDowntimeRemoved,
+}
+
+// objectState is the local state of all objects in stream mode.  While the
+// event stream is not connected, the state is resynced on every collection.
+type objectState struct {
+	resync sync.Mutex // Serializing resyncs
+
+	mu         sync.Mutex // Protecting the fields below
+	connected  bool
+	generation int // counting (dis)connects, to not mark outdated resyncs as synced
+	resyncing  bool
+	pending    []event // received while resyncing, applied after the resync
+	synced     time.Time
+	hosts      map[string]Host    // by name
+	services   map[string]service // by host!service
+}
+
+func serviceKey(host, service string) string {
+	return host + "!" + service
+}
+
+// connect marks the event stream as connected and forces a resync on the next
+// collection, as events might have been missed.
+func (s *objectState) connect() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.connected = true
+	s.generation++
+	s.synced = time.Time{}
+}
+
+// disconnect marks the event stream as not connected, forcing a resync on
+// every collection until it is connected again.  It returns whether the
+// stream was connected before.
+func (s *objectState) disconnect() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wasConnected := s.connected
+	s.connected = false
+	s.generation++
+	s.synced = time.Time{}
+
+	return wasConnected
+}
+
+// streamedObjects returns the local state of all objects, resyncing it when
+// it is missing or outdated.  The objects are fetched without holding the
+// state, to not block the event stream meanwhile.
+func (c *Connector) streamedObjects(ctx context.Context) (map[string]HostAttrs, []serviceAttrs, error) {
+	s := &c.state
+	s.resync.Lock()
+	defer s.resync.Unlock()
+
+	if generation, ok := s.startResync(c.config.ResyncInterval); ok {
+		slog.DebugContext(ctx, "resyncing icinga2 objects", slog.String("url", c.config.URL))
+
+		hosts, err := c.collectHosts(ctx)
+		if err != nil {
+			s.abortResync()
+			return nil, nil, err
+		}
+		services, err := c.collectServices(ctx)
+		if err != nil {
+			s.abortResync()
+			return nil, nil, err
+		}
+
+		s.finishResync(generation, hosts, services)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hosts := make(map[string]HostAttrs, len(s.hosts))
+	for _, h := range s.hosts {
+		hosts[h.DisplayName] = HostAttrs{Host: h}
+	}
+	services := make([]serviceAttrs, 0, len(s.services))
+	for _, svc := range s.services {
+		services = append(services, serviceAttrs{Service: svc})
+	}
+
+	return hosts, services, nil
+}
+
+// startResync returns whether a resync is needed, recording the events
+// received meanwhile.
+func (s *objectState) startResync(interval time.Duration) (int, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.connected && time.Since(s.synced) <= interval {
+		return s.generation, false
+	}
+
+	s.resyncing = true
+	s.pending = nil
+	return s.generation, true
+}
+
+// abortResync ends a failed resync, keeping the previous state.
+func (s *objectState) abortResync() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resyncing = false
+	s.pending = nil
+}
+
+// finishResync swaps in the fetched objects and applies the events received
+// meanwhile.  The state is only marked as synced, if the stream was not
+// (dis)connected meanwhile.
+func (s *objectState) finishResync(generation int, hosts map[string]HostAttrs, services []serviceAttrs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pending := s.pending
+	s.resyncing = false
+	s.pending = nil
+
+	s.hosts = make(map[string]Host, len(hosts))
+	for _, h := range hosts {
+		s.hosts[h.Host.Name] = h.Host
+	}
+	s.services = make(map[string]service, len(services))
+	for _, svc := range services {
+		s.services[serviceKey(svc.Service.HostName, svc.Service.Name)] = svc.Service
+	}
+	for _, e := range pending {
+		s.update(e)
+	}
+
+	if generation == s.generation {
+		s.synced = time.Now()
+	}
+}
+
+// Stream updates the local object state from the event stream, if enabled.
+// Every connection is limited to the resync interval, to notice stale
+// connections.  As events might have been missed while not connected, every
+// new connection forces a resync.  While not connected, every collection
+// resyncs, to not show outdated states.
+//
+// see https://icinga.com/docs/icinga-2/latest/doc/12-icinga2-api/#event-streams
+func (c *Connector) Stream(ctx context.Context, changed func()) {
+	if !c.config.Stream {
+		return
+	}
+
+	delay := streamRetryDelay
+	for {
+		err := c.stream(ctx, changed)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			// The new connection forces a resync
+			slog.DebugContext(ctx, "reconnecting icinga2 event stream", slog.String("url", c.config.URL))
+			continue
+		}
+
+		if c.state.disconnect() {
+			delay = streamRetryDelay
+			changed()
+		}
+
+		slog.WarnContext(ctx, "icinga2 event stream failed",
+			slog.String("url", c.config.URL),
+			slog.Duration("retry", delay),
+			slog.Any("error", err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, streamMaxRetryDelay)
+	}
+}
+
+func (c *Connector) stream(ctx context.Context, changed func()) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.ResyncInterval)
+	defer cancel()
+
+	payload := map[string]interface{}{
+		"queue": "tuwat-" + connectors.RandStringBytesMaskImpr(16),
+		"types": streamTypes,
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL+"/v1/events", buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to get /v1/events, status code %d: %s", res.StatusCode, string(b))
+	}
+
+	c.state.connect()
+	changed()
+
+	decoder := json.NewDecoder(res.Body)
+	for {
+		var e event
+		if err := decoder.Decode(&e); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		if c.state.apply(e) {
+			changed()
+		}
+	}
+}
+
+// apply updates the local state with the event, returning whether anything
+// changed.  Events for unknown objects are ignored, as they might be filtered.
+// New objects are known after the next resync.
+func (s *objectState) apply(e event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resyncing {
+		// The resync might have fetched the objects before the event
+		s.pending = append(s.pending, e)
+	}
+
+	if s.synced.IsZero() {
+		// The next collection resyncs anyway
+		return true
+	}
+
+	return s.update(e)
+}
+
+func (s *objectState) update(e event) bool {
+	host, svc := e.Host, e.Service
+	if e.Downtime != nil {
+		host, svc = e.Downtime.HostName, e.Downtime.ServiceName
+	}
+
+	if svc == "" {
+		h, ok := s.hosts[host]
+		if !ok {
+			return false
+		}
+		applyEvent(e, &h.State, &h.LastStateChange, &h.Acknowledgement, &h.DowntimeDepth, &h.Output)
+		s.hosts[host] = h
+	} else {
+		key := serviceKey(host, svc)
+		service, ok := s.services[key]
+		if !ok {
+			return false
+		}
+		applyEvent(e, &service.State, &service.LastStateChange, &service.Acknowledgement, &service.DowntimeDepth, &service.LastCheckResult.Output)
+		s.services[key] = service
+	}
+
+	return true
+}
+
+func applyEvent(e event, state *int, lastStateChange *float64, acknowledgement, downtimeDepth *int, output *string) {
+	switch e.Type {
+	case eventStateChange:
+		if *state != e.State {
+			*lastStateChange = e.Timestamp
+		}
+		*state = e.State
+		if e.CheckResult != nil {
+			*output = e.CheckResult.Output
+		}
+		if e.DowntimeDepth != nil {
+			*downtimeDepth = *e.DowntimeDepth
+		}
+		if e.Acknowledgement != nil && !*e.Acknowledgement {
+			*acknowledgement = 0
+		} else if e.Acknowledgement != nil && *acknowledgement == 0 {
+			*acknowledgement = 1
+		}
+	case eventAcknowledgementSet:
+		*acknowledgement = max(e.AcknowledgementType, 1)
+	case eventAcknowledgementCleared:
+		*acknowledgement = 0
+	case eventDowntimeStarted:
+		*downtimeDepth++
+	case eventDowntimeRemoved:
+		// Removing a downtime, which did not start yet, does not change the
+		// depth.  That is corrected by the next resync.
+		if *downtimeDepth > 0 {
+			*downtimeDepth--
+		}
+	}
+}
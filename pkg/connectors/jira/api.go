@@ -0,0 +1,53 @@
+package jira
+
+// https://developer.atlassian.com/cloud/jira/platform/rest/v2/api-group-issue-search/
+
+// searchResult supports both the offset based pagination of Jira Server/Data
+// Center and the token based pagination of the newer Jira Cloud endpoint.
+type searchResult struct {
+	StartAt       int     `json:"startAt"`
+	MaxResults    int     `json:"maxResults"`
+	Total         int     `json:"total"`
+	NextPageToken string  `json:"nextPageToken"`
+	IsLast        bool    `json:"isLast"`
+	Issues        []issue `json:"issues"`
+}
+
+type issue struct {
+	ID     string `json:"id"`
+	Key    string `json:"key"`
+	Fields fields `json:"fields"`
+}
+
+type fields struct {
+	Summary    string      `json:"summary"`
+	Created    string      `json:"created"`
+	DueDate    string      `json:"duedate"`
+	Labels     []string    `json:"labels"`
+	Components []namedItem `json:"components"`
+	Status     namedItem   `json:"status"`
+	Priority   namedItem   `json:"priority"`
+	IssueType  namedItem   `json:"issuetype"`
+	Project    project     `json:"project"`
+	Assignee   *user       `json:"assignee"`
+	Reporter   *user       `json:"reporter"`
+}
+
+type namedItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type project struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+type user struct {
+	DisplayName string `json:"displayName"`
+}
+
+// createdLayout is the timestamp format used by Jira, which is not quite
+// RFC3339.
+const createdLayout = "2006-01-02T15:04:05.000-0700"
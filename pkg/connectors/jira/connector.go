@@ -0,0 +1,234 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	Tag string
+	// JQL selects the issues to show, defaults to the unresolved issues
+	// assigned to the current user.
+	JQL string
+	// SearchPath is the search endpoint, `/rest/api/3/search/jql` for
+	// Jira Cloud.
+	SearchPath string
+	// Priorities maps priority names onto `ok`, `warning`, `critical` or
+	// `unknown`.  Issues with other priorities are warnings.  Overdue issues
+	// are always critical.
+	Priorities map[string]string
+	MaxPages   int
+	common.HTTPConfig
+}
+
+const pageSize = 100
+
+var searchFields = []string{
+	"summary", "created", "duedate", "labels", "components", "status",
+	"priority", "issuetype", "project", "assignee", "reporter",
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.JQL == "" {
+		cfg.JQL = "assignee = currentUser() AND resolution = Unresolved ORDER BY priority DESC"
+	}
+	if cfg.SearchPath == "" {
+		cfg.SearchPath = "/rest/api/2/search"
+	}
+	if cfg.Priorities == nil {
+		cfg.Priorities = map[string]string{
+			"Highest":  "critical",
+			"Blocker":  "critical",
+			"Critical": "critical",
+		}
+	}
+	if cfg.MaxPages == 0 {
+		cfg.MaxPages = 10
+	}
+
+	return &Connector{*cfg, cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	issues, err := c.collectIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, issue := range issues {
+		state := c.fromIssue(issue)
+		if state == connectors.OK {
+			continue
+		}
+
+		start, err := time.Parse(createdLayout, issue.Fields.Created)
+		if err != nil {
+			slog.ErrorContext(ctx, "Cannot parse", slog.String("created", issue.Fields.Created), slog.Any("error", err))
+		}
+
+		var components []string
+		for _, component := range issue.Fields.Components {
+			components = append(components, component.Name)
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Project":    issue.Fields.Project.Key,
+				"Ticket":     issue.Key,
+				"Source":     c.config.URL,
+				"Type":       "Ticket",
+				"IssueType":  issue.Fields.IssueType.Name,
+				"Status":     issue.Fields.Status.Name,
+				"Priority":   issue.Fields.Priority.Name,
+				"Due":        issue.Fields.DueDate,
+				"Components": strings.Join(components, ","),
+				"Labels":     strings.Join(issue.Fields.Labels, ","),
+				"Assigned":   displayName(issue.Fields.Assignee),
+				"Author":     displayName(issue.Fields.Reporter),
+			},
+			Start:       start,
+			State:       state,
+			Description: issue.Fields.Summary,
+			Details:     fmt.Sprintf("%s %s: %s", issue.Fields.IssueType.Name, issue.Key, issue.Fields.Status.Name),
+			Links: []html.HTML{
+				html.HTML("<a href=\"" + c.config.URL + "/browse/" + issue.Key + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			},
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Jira (%s)", c.config.URL)
+}
+
+// collectIssues runs the JQL search, following either the offset or the
+// token based pagination, whichever the server uses.
+func (c *Connector) collectIssues(ctx context.Context) ([]issue, error) {
+	query := map[string]string{
+		"jql":        c.config.JQL,
+		"fields":     strings.Join(searchFields, ","),
+		"maxResults": strconv.Itoa(pageSize),
+	}
+
+	var issues []issue
+	for page := 0; page < c.config.MaxPages; page++ {
+		result, err := c.search(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, result.Issues...)
+
+		if result.NextPageToken != "" && !result.IsLast {
+			query["nextPageToken"] = result.NextPageToken
+		} else if result.NextPageToken == "" && len(result.Issues) > 0 && result.StartAt+len(result.Issues) < result.Total {
+			query["startAt"] = strconv.Itoa(result.StartAt + len(result.Issues))
+		} else {
+			break
+		}
+	}
+
+	return issues, nil
+}
+
+func (c *Connector) search(ctx context.Context, query map[string]string) (searchResult, error) {
+	slog.DebugContext(ctx, "getting issues", slog.String("url", c.config.URL+c.config.SearchPath))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+c.config.SearchPath, nil)
+	if err != nil {
+		return searchResult{}, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	q := req.URL.Query()
+	for k, v := range query {
+		q.Set(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return searchResult{}, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return searchResult{}, fmt.Errorf("failed to search issues, status code %d: %s", res.StatusCode, string(b))
+	}
+
+	var result searchResult
+	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+c.config.SearchPath),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return searchResult{}, err
+	}
+
+	return result, nil
+}
+
+// fromIssue maps the priority onto a state and escalates issues which are due
+// today to a warning, and overdue issues to critical.
+func (c *Connector) fromIssue(issue issue) connectors.State {
+	state := connectors.Warning
+	if mapped, ok := c.config.Priorities[issue.Fields.Priority.Name]; ok {
+		state = parseState(mapped)
+	}
+
+	if issue.Fields.DueDate == "" {
+		return state
+	}
+
+	today := time.Now().Format("2006-01-02")
+	if issue.Fields.DueDate < today {
+		return connectors.Critical
+	} else if issue.Fields.DueDate == today && state == connectors.OK {
+		return connectors.Warning
+	}
+	return state
+}
+
+func parseState(state string) connectors.State {
+	switch strings.ToLower(state) {
+	case "ok":
+		return connectors.OK
+	case "warning":
+		return connectors.Warning
+	case "critical":
+		return connectors.Critical
+	}
+	return connectors.Unknown
+}
+
+func displayName(u *user) string {
+	if u == nil {
+		return ""
+	}
+	return u.DisplayName
+}
@@ -0,0 +1,158 @@
+package jira
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if user, pass, ok := req.BasicAuth(); !ok || user != "jo" || pass != "secret" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if req.URL.Path != "/rest/api/2/search" || req.URL.Query().Get("jql") != "project = OPS" {
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		switch req.URL.Query().Get("startAt") {
+		case "":
+			_, _ = res.Write([]byte(fmt.Sprintf(mockSearchPage, 0, "OPS-1", "High", "OPS-2", "", "Low")))
+		case "2":
+			_, _ = res.Write([]byte(fmt.Sprintf(mockSearchPage, 2, "OPS-3", "Highest", "OPS-4", yesterday, "Low")))
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag: "test",
+		JQL: "project = OPS",
+		Priorities: map[string]string{
+			"Highest": "critical",
+			"Low":     "ok",
+		},
+		HTTPConfig: common.HTTPConfig{
+			URL:      mockServer.URL,
+			Username: "jo",
+			Password: "secret",
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 3 {
+		t.Fatal("There should be alerts from both pages, without low priority ones", alerts)
+	}
+
+	if alerts[0].Labels["Ticket"] != "OPS-1" || alerts[0].State != connectors.Warning {
+		t.Error("Unmapped priorities should be warnings", alerts[0])
+	}
+	if alerts[0].Labels["Components"] != "backend,database" || alerts[0].Labels["Labels"] != "infra" || alerts[0].Labels["Assigned"] != "Jo Doe" {
+		t.Error("Fields should be labels", alerts[0].Labels)
+	}
+	if alerts[0].Start.IsZero() {
+		t.Error("Creation date should be parsed", alerts[0])
+	}
+	if alerts[1].Labels["Ticket"] != "OPS-3" || alerts[1].State != connectors.Critical {
+		t.Error("Priorities should be mapped", alerts[1])
+	}
+	if alerts[2].Labels["Ticket"] != "OPS-4" || alerts[2].State != connectors.Critical {
+		t.Error("Overdue issues should be critical", alerts[2])
+	}
+}
+
+func TestTokenPagination(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer example" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		switch req.URL.Query().Get("nextPageToken") {
+		case "":
+			_, _ = res.Write([]byte(`{"isLast": false, "nextPageToken": "abc", "issues": [{"key": "OPS-1", "fields": {"summary": "first"}}]}`))
+		case "abc":
+			_, _ = res.Write([]byte(`{"isLast": true, "issues": [{"key": "OPS-2", "fields": {"summary": "second"}}]}`))
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:        "test",
+		SearchPath: "/rest/api/3/search/jql",
+		HTTPConfig: common.HTTPConfig{
+			URL:         mockServer.URL,
+			BearerToken: "example",
+		},
+	}
+
+	alerts, err := NewConnector(&cfg).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 || alerts[1].Description != "second" {
+		t.Error("Token based pagination should be followed", alerts)
+	}
+}
+
+const mockSearchPage = `
+{
+  "expand": "schema,names",
+  "startAt": %d,
+  "maxResults": 2,
+  "total": 4,
+  "issues": [
+    {
+      "id": "10001",
+      "key": "%s",
+      "fields": {
+        "summary": "Database failover test",
+        "created": "2024-05-02T10:11:12.000+0200",
+        "duedate": null,
+        "labels": ["infra"],
+        "components": [{"id": "1", "name": "backend"}, {"id": "2", "name": "database"}],
+        "status": {"id": "3", "name": "In Progress"},
+        "priority": {"id": "2", "name": "%s"},
+        "issuetype": {"id": "10002", "name": "Task"},
+        "project": {"id": "10000", "key": "OPS", "name": "Operations"},
+        "assignee": {"displayName": "Jo Doe"},
+        "reporter": {"displayName": "Max Mustermann"}
+      }
+    },
+    {
+      "id": "10002",
+      "key": "%s",
+      "fields": {
+        "summary": "Rotate certificates",
+        "created": "2024-05-01T08:00:00.000+0000",
+        "duedate": "%s",
+        "labels": [],
+        "components": [],
+        "status": {"id": "1", "name": "Open"},
+        "priority": {"id": "4", "name": "%s"},
+        "issuetype": {"id": "10002", "name": "Task"},
+        "project": {"id": "10000", "key": "OPS", "name": "Operations"},
+        "assignee": null,
+        "reporter": {"displayName": "Jo Doe"}
+      }
+    }
+  ]
+}
+`
@@ -0,0 +1,114 @@
+package kubernetes
+
+// Minimal subsets of the Kubernetes API objects.
+//
+// https://kubernetes.io/docs/reference/kubernetes-api/
+
+type list[T any] struct {
+	Metadata listMeta `json:"metadata"`
+	Items    []T      `json:"items"`
+}
+
+type listMeta struct {
+	Continue string `json:"continue"`
+}
+
+type objectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace"`
+	UID               string            `json:"uid"`
+	CreationTimestamp string            `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels"`
+}
+
+type condition struct {
+	Type               string `json:"type"`
+	Status             string `json:"status"`
+	Reason             string `json:"reason"`
+	Message            string `json:"message"`
+	LastTransitionTime string `json:"lastTransitionTime"`
+}
+
+type pod struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		NodeName string `json:"nodeName"`
+	} `json:"spec"`
+	Status struct {
+		Phase                 string            `json:"phase"`
+		StartTime             string            `json:"startTime"`
+		InitContainerStatuses []containerStatus `json:"initContainerStatuses"`
+		ContainerStatuses     []containerStatus `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+type containerStatus struct {
+	Name         string         `json:"name"`
+	RestartCount int            `json:"restartCount"`
+	State        containerState `json:"state"`
+	LastState    containerState `json:"lastState"`
+}
+
+type containerState struct {
+	Waiting *struct {
+		Reason  string `json:"reason"`
+		Message string `json:"message"`
+	} `json:"waiting"`
+	Terminated *struct {
+		Reason     string `json:"reason"`
+		Message    string `json:"message"`
+		ExitCode   int    `json:"exitCode"`
+		FinishedAt string `json:"finishedAt"`
+	} `json:"terminated"`
+}
+
+type job struct {
+	Metadata objectMeta `json:"metadata"`
+	Status   struct {
+		Failed     int         `json:"failed"`
+		Conditions []condition `json:"conditions"`
+	} `json:"status"`
+}
+
+type deployment struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		Replicas *int `json:"replicas"`
+	} `json:"spec"`
+	Status struct {
+		Replicas            int         `json:"replicas"`
+		AvailableReplicas   int         `json:"availableReplicas"`
+		UnavailableReplicas int         `json:"unavailableReplicas"`
+		Conditions          []condition `json:"conditions"`
+	} `json:"status"`
+}
+
+type node struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		Unschedulable bool `json:"unschedulable"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []condition `json:"conditions"`
+	} `json:"status"`
+}
+
+type event struct {
+	Metadata       objectMeta `json:"metadata"`
+	InvolvedObject struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"involvedObject"`
+	Reason         string `json:"reason"`
+	Message        string `json:"message"`
+	Type           string `json:"type"`
+	Count          int    `json:"count"`
+	FirstTimestamp string `json:"firstTimestamp"`
+	LastTimestamp  string `json:"lastTimestamp"`
+	EventTime      string `json:"eventTime"`
+	Series         *struct {
+		Count            int    `json:"count"`
+		LastObservedTime string `json:"lastObservedTime"`
+	} `json:"series"`
+}
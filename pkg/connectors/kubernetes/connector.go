@@ -0,0 +1,382 @@
+// Package kubernetes reports unhealthy workloads and nodes by talking to the
+// Kubernetes API server directly.
+package kubernetes
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	Tag     string
+	Cluster string
+	// Kubeconfig is the path to a kubeconfig file, Context selects another
+	// than its current context.  Without Kubeconfig and URL, the service
+	// account of the pod tuwat is running in is used.
+	Kubeconfig string
+	Context    string
+	// Namespaces restricts namespaced resources, defaults to all namespaces.
+	Namespaces []string
+	// Warning events are shown if they occurred at least EventMinCount
+	// times, and were last seen within EventWindow.
+	EventMinCount int
+	EventWindow   time.Duration
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.EventMinCount == 0 {
+		cfg.EventMinCount = 5
+	}
+	if cfg.EventWindow == 0 {
+		cfg.EventWindow = time.Hour
+	}
+
+	var rest *restConfig
+	var err error
+	switch {
+	case cfg.Kubeconfig != "":
+		rest, err = loadKubeconfig(cfg.Kubeconfig, cfg.Context)
+	case cfg.URL == "":
+		rest, err = inClusterConfig(cfg.HTTPConfig)
+	default:
+		rest = &restConfig{HTTPConfig: cfg.HTTPConfig, tlsConfig: &tls.Config{InsecureSkipVerify: cfg.Insecure}}
+	}
+	if err != nil {
+		panic(fmt.Errorf("kubernetes %s: %w", cfg.Tag, err))
+	}
+	cfg.HTTPConfig = rest.HTTPConfig
+
+	return &Connector{*cfg, rest.client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	var alerts []connectors.Alert
+
+	for _, collect := range []func(context.Context) ([]connectors.Alert, error){
+		c.collectNodes,
+		c.collectPods,
+		c.collectDeployments,
+		c.collectJobs,
+		c.collectEvents,
+	} {
+		a, err := collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, a...)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Kubernetes (%s)", c.config.URL)
+}
+
+func (c *Connector) collectNodes(ctx context.Context) ([]connectors.Alert, error) {
+	nodes, err := listAll[node](ctx, c, []string{"/api/v1/nodes"}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, n := range nodes {
+		ready := findCondition(n.Status.Conditions, "Ready")
+		if ready == nil || ready.Status == "True" {
+			continue
+		}
+
+		alert := c.alert("Node", "", n.Metadata.Name, ready.Reason)
+		alert.Labels["Hostname"] = n.Metadata.Name
+		alert.Start = parseTime(ctx, ready.LastTransitionTime)
+		alert.State = connectors.Critical
+		alert.Description = fmt.Sprintf("Node %s not ready", n.Metadata.Name)
+		alert.Details = ready.Message
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) collectPods(ctx context.Context) ([]connectors.Alert, error) {
+	pods, err := listAll[pod](ctx, c, c.namespaced("/api/v1", "pods"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, p := range pods {
+		var crashing []string
+		for _, cs := range append(p.Status.InitContainerStatuses, p.Status.ContainerStatuses...) {
+			if cs.State.Waiting == nil || cs.State.Waiting.Reason != "CrashLoopBackOff" {
+				continue
+			}
+
+			detail := fmt.Sprintf("%s restarted %d times", cs.Name, cs.RestartCount)
+			if t := cs.LastState.Terminated; t != nil {
+				detail += fmt.Sprintf(", last exit code %d (%s)", t.ExitCode, t.Reason)
+			}
+			crashing = append(crashing, detail)
+		}
+		if len(crashing) == 0 {
+			continue
+		}
+
+		alert := c.alert("Pod", p.Metadata.Namespace, p.Metadata.Name, "CrashLoopBackOff")
+		alert.Labels["Hostname"] = p.Spec.NodeName
+		alert.Start = parseTime(ctx, p.Status.StartTime)
+		alert.State = connectors.Critical
+		alert.Description = fmt.Sprintf("Pod %s crash looping", p.Metadata.Name)
+		alert.Details = strings.Join(crashing, "\n")
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) collectDeployments(ctx context.Context) ([]connectors.Alert, error) {
+	deployments, err := listAll[deployment](ctx, c, c.namespaced("/apis/apps/v1", "deployments"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, d := range deployments {
+		desired := 1
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if desired == 0 || d.Status.UnavailableReplicas == 0 {
+			continue
+		}
+
+		state := connectors.Warning
+		if d.Status.AvailableReplicas == 0 {
+			state = connectors.Critical
+		}
+
+		reason, details, since := "", "", ""
+		if available := findCondition(d.Status.Conditions, "Available"); available != nil {
+			reason, details, since = available.Reason, available.Message, available.LastTransitionTime
+		}
+
+		alert := c.alert("Deployment", d.Metadata.Namespace, d.Metadata.Name, reason)
+		alert.Start = parseTime(ctx, since)
+		alert.State = state
+		alert.Description = fmt.Sprintf("Deployment %s has %d/%d replicas unavailable", d.Metadata.Name, d.Status.UnavailableReplicas, desired)
+		alert.Details = details
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) collectJobs(ctx context.Context) ([]connectors.Alert, error) {
+	jobs, err := listAll[job](ctx, c, c.namespaced("/apis/batch/v1", "jobs"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, j := range jobs {
+		failed := findCondition(j.Status.Conditions, "Failed")
+		if failed == nil || failed.Status != "True" {
+			continue
+		}
+
+		alert := c.alert("Job", j.Metadata.Namespace, j.Metadata.Name, failed.Reason)
+		alert.Start = parseTime(ctx, failed.LastTransitionTime)
+		alert.State = connectors.Warning
+		alert.Description = fmt.Sprintf("Job %s failed", j.Metadata.Name)
+		alert.Details = failed.Message
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+// collectEvents reports repeated warning events, once per involved object and
+// reason.
+func (c *Connector) collectEvents(ctx context.Context) ([]connectors.Alert, error) {
+	events, err := listAll[event](ctx, c, c.namespaced("/api/v1", "events"), url.Values{"fieldSelector": {"type=Warning"}})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]int)
+	var alerts []connectors.Alert
+	for _, e := range events {
+		count, last := e.Count, e.LastTimestamp
+		if e.Series != nil {
+			count, last = e.Series.Count, e.Series.LastObservedTime
+		}
+		if last == "" {
+			last = e.EventTime
+		}
+		if count < c.config.EventMinCount || time.Since(parseTime(ctx, last)) > c.config.EventWindow {
+			continue
+		}
+
+		start := e.FirstTimestamp
+		if start == "" {
+			start = e.EventTime
+		}
+
+		obj := e.InvolvedObject
+		alert := c.alert("Event", e.Metadata.Namespace, obj.Name, e.Reason)
+		alert.Labels["Kind"] = obj.Kind
+		alert.Labels["Count"] = strconv.Itoa(count)
+		alert.Start = parseTime(ctx, start)
+		alert.State = connectors.Warning
+		alert.Description = fmt.Sprintf("%s: %s %s", e.Reason, obj.Kind, obj.Name)
+		alert.Details = e.Message
+
+		key := e.Metadata.Namespace + "/" + obj.Kind + "/" + obj.Name + "/" + e.Reason
+		if i, ok := seen[key]; ok {
+			if alerts[i].Start.After(alert.Start) {
+				alerts[i].Start = alert.Start
+			}
+			continue
+		}
+		seen[key] = len(alerts)
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) alert(kind, namespace, name, reason string) connectors.Alert {
+	return connectors.Alert{
+		Labels: map[string]string{
+			"Cluster":   c.config.Cluster,
+			"Namespace": namespace,
+			"Name":      name,
+			"Reason":    reason,
+			"Source":    c.config.URL,
+			"Type":      kind,
+		},
+	}
+}
+
+// namespaced returns the paths to list a resource, either across all
+// namespaces or per configured namespace.
+func (c *Connector) namespaced(prefix, resource string) []string {
+	if len(c.config.Namespaces) == 0 {
+		return []string{prefix + "/" + resource}
+	}
+
+	var paths []string
+	for _, ns := range c.config.Namespaces {
+		paths = append(paths, prefix+"/namespaces/"+url.PathEscape(ns)+"/"+resource)
+	}
+	return paths
+}
+
+// listAll lists all objects of the given paths, following the paginated
+// responses of the API server.
+func listAll[T any](ctx context.Context, c *Connector, paths []string, query url.Values) ([]T, error) {
+	var items []T
+
+	for _, path := range paths {
+		cont := ""
+		for {
+			var l list[T]
+			if err := c.get(ctx, path, query, cont, &l); err != nil {
+				return nil, err
+			}
+			items = append(items, l.Items...)
+
+			cont = l.Metadata.Continue
+			if cont == "" {
+				break
+			}
+		}
+	}
+
+	return items, nil
+}
+
+func (c *Connector) get(ctx context.Context, path string, query url.Values, cont string, v any) error {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+path))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	q := req.URL.Query()
+	for k, v := range query {
+		q[k] = v
+	}
+	q.Set("limit", "500")
+	if cont != "" {
+		q.Set("continue", cont)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to list %s, status code %d: %s", path, res.StatusCode, string(b))
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+path),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+func findCondition(conditions []condition, conditionType string) *condition {
+	for i := range conditions {
+		if conditions[i].Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func parseTime(ctx context.Context, s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse", slog.String("time", s), slog.Any("error", err))
+	}
+	return t
+}
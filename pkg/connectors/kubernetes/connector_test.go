@@ -0,0 +1,302 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	mockServer := httptest.NewServer(fakeAPIServer(t))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:     "test",
+		Cluster: "prod",
+		HTTPConfig: common.HTTPConfig{
+			URL:         mockServer.URL,
+			BearerToken: "example",
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byType := make(map[string]connectors.Alert)
+	for _, alert := range alerts {
+		if alert.Labels["Cluster"] != "prod" {
+			t.Error("Alerts should carry the cluster label", alert)
+		}
+		byType[alert.Labels["Type"]] = alert
+	}
+	if len(alerts) != 5 || len(byType) != 5 {
+		t.Fatal("There should be one alert per unhealthy object", alerts)
+	}
+
+	if n := byType["Node"]; n.State != connectors.Critical || n.Labels["Hostname"] != "worker-2" {
+		t.Error("NotReady nodes should be critical", n)
+	}
+	if p := byType["Pod"]; p.State != connectors.Critical || p.Labels["Namespace"] != "shop" || p.Labels["Name"] != "api-7d4b9c-x2x9q" {
+		t.Error("Crash looping pods should be critical", p)
+	}
+	if d := byType["Deployment"]; d.State != connectors.Warning || d.Labels["Name"] != "api" {
+		t.Error("Deployments with unavailable replicas should be warnings", d)
+	}
+	if j := byType["Job"]; j.State != connectors.Warning || j.Labels["Reason"] != "BackoffLimitExceeded" {
+		t.Error("Failed jobs should be warnings", j)
+	}
+	if e := byType["Event"]; e.Labels["Kind"] != "PersistentVolumeClaim" || e.Labels["Count"] != "12" {
+		t.Error("Repeated warning events should be reported", e)
+	}
+}
+
+func TestKubeconfig(t *testing.T) {
+	mockServer := httptest.NewTLSServer(fakeAPIServer(t))
+	defer func() { mockServer.Close() }()
+
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: mockServer.Certificate().Raw})
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	content := fmt.Sprintf(mockKubeconfig, mockServer.URL, base64.StdEncoding.EncodeToString(ca))
+	if err := os.WriteFile(kubeconfig, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{
+		Tag:        "test",
+		Cluster:    "prod",
+		Kubeconfig: kubeconfig,
+		Namespaces: []string{"shop"},
+	}
+
+	connector := NewConnector(&cfg)
+	if connector.config.URL != mockServer.URL {
+		t.Error("Server should be taken from the kubeconfig", connector.config.URL)
+	}
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 5 {
+		t.Error("There should be alerts via the kubeconfig", alerts)
+	}
+}
+
+func fakeAPIServer(t *testing.T) http.Handler {
+	recent := time.Now().Add(-5 * time.Minute).UTC().Format(time.RFC3339)
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer example" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body string
+		switch req.URL.Path {
+		case "/api/v1/nodes":
+			body = mockNodes
+		case "/api/v1/pods", "/api/v1/namespaces/shop/pods":
+			if req.URL.Query().Get("continue") == "" {
+				body = mockPodsPage1
+			} else {
+				body = mockPodsPage2
+			}
+		case "/apis/apps/v1/deployments", "/apis/apps/v1/namespaces/shop/deployments":
+			body = mockDeployments
+		case "/apis/batch/v1/jobs", "/apis/batch/v1/namespaces/shop/jobs":
+			body = mockJobs
+		case "/api/v1/events", "/api/v1/namespaces/shop/events":
+			if req.URL.Query().Get("fieldSelector") != "type=Warning" {
+				t.Error("Only warning events should be requested")
+			}
+			body = fmt.Sprintf(mockEvents, recent, recent)
+		default:
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	})
+}
+
+const mockKubeconfig = `
+apiVersion: v1
+kind: Config
+current-context: prod
+clusters:
+- name: prod
+  cluster:
+    server: %s
+    certificate-authority-data: %s
+users:
+- name: tuwat
+  user:
+    token: example
+contexts:
+- name: prod
+  context:
+    cluster: prod
+    user: tuwat
+    namespace: default
+`
+
+const mockNodes = `
+{
+  "kind": "NodeList",
+  "apiVersion": "v1",
+  "metadata": {"resourceVersion": "1"},
+  "items": [
+    {
+      "metadata": {"name": "worker-1"},
+      "status": {"conditions": [{"type": "Ready", "status": "True", "reason": "KubeletReady"}]}
+    },
+    {
+      "metadata": {"name": "worker-2"},
+      "status": {"conditions": [
+        {"type": "MemoryPressure", "status": "Unknown", "reason": "NodeStatusUnknown"},
+        {"type": "Ready", "status": "Unknown", "reason": "NodeStatusUnknown", "message": "Kubelet stopped posting node status.", "lastTransitionTime": "2024-05-02T10:11:12Z"}
+      ]}
+    }
+  ]
+}
+`
+
+const mockPodsPage1 = `
+{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "metadata": {"resourceVersion": "1", "continue": "eyJ2IjoibWV0YS5rOHMuaW8vdjEifQ"},
+  "items": [
+    {
+      "metadata": {"name": "web-5f6d8-abcde", "namespace": "shop"},
+      "spec": {"nodeName": "worker-1"},
+      "status": {"phase": "Running", "startTime": "2024-05-01T08:00:00Z", "containerStatuses": [
+        {"name": "web", "restartCount": 0, "state": {"running": {"startedAt": "2024-05-01T08:00:05Z"}}}
+      ]}
+    }
+  ]
+}
+`
+
+const mockPodsPage2 = `
+{
+  "kind": "PodList",
+  "apiVersion": "v1",
+  "metadata": {"resourceVersion": "1"},
+  "items": [
+    {
+      "metadata": {"name": "api-7d4b9c-x2x9q", "namespace": "shop"},
+      "spec": {"nodeName": "worker-1"},
+      "status": {"phase": "Running", "startTime": "2024-05-02T09:00:00Z", "containerStatuses": [
+        {
+          "name": "api",
+          "restartCount": 17,
+          "state": {"waiting": {"reason": "CrashLoopBackOff", "message": "back-off 5m0s restarting failed container"}},
+          "lastState": {"terminated": {"reason": "Error", "exitCode": 1, "finishedAt": "2024-05-02T10:00:00Z"}}
+        }
+      ]}
+    }
+  ]
+}
+`
+
+const mockDeployments = `
+{
+  "kind": "DeploymentList",
+  "apiVersion": "apps/v1",
+  "metadata": {"resourceVersion": "1"},
+  "items": [
+    {
+      "metadata": {"name": "api", "namespace": "shop"},
+      "spec": {"replicas": 3},
+      "status": {"replicas": 3, "availableReplicas": 2, "unavailableReplicas": 1, "conditions": [
+        {"type": "Available", "status": "True", "reason": "MinimumReplicasAvailable", "message": "Deployment has minimum availability.", "lastTransitionTime": "2024-05-02T09:00:00Z"}
+      ]}
+    },
+    {
+      "metadata": {"name": "web", "namespace": "shop"},
+      "spec": {"replicas": 2},
+      "status": {"replicas": 2, "availableReplicas": 2}
+    },
+    {
+      "metadata": {"name": "batch", "namespace": "shop"},
+      "spec": {"replicas": 0},
+      "status": {}
+    }
+  ]
+}
+`
+
+const mockJobs = `
+{
+  "kind": "JobList",
+  "apiVersion": "batch/v1",
+  "metadata": {"resourceVersion": "1"},
+  "items": [
+    {
+      "metadata": {"name": "report-28577280", "namespace": "shop"},
+      "status": {"failed": 6, "conditions": [
+        {"type": "Failed", "status": "True", "reason": "BackoffLimitExceeded", "message": "Job has reached the specified backoff limit", "lastTransitionTime": "2024-05-02T06:00:00Z"}
+      ]}
+    },
+    {
+      "metadata": {"name": "report-28577281", "namespace": "shop"},
+      "status": {"succeeded": 1, "conditions": [{"type": "Complete", "status": "True"}]}
+    }
+  ]
+}
+`
+
+const mockEvents = `
+{
+  "kind": "EventList",
+  "apiVersion": "v1",
+  "metadata": {"resourceVersion": "1"},
+  "items": [
+    {
+      "metadata": {"name": "data-db-0.17ca", "namespace": "shop"},
+      "involvedObject": {"kind": "PersistentVolumeClaim", "name": "data-db-0", "namespace": "shop"},
+      "reason": "ProvisioningFailed",
+      "message": "storageclass.storage.k8s.io \"fast\" not found",
+      "type": "Warning",
+      "count": 12,
+      "firstTimestamp": "2024-05-02T08:00:00Z",
+      "lastTimestamp": "%s"
+    },
+    {
+      "metadata": {"name": "api-7d4b9c-x2x9q.17cb", "namespace": "shop"},
+      "involvedObject": {"kind": "Pod", "name": "api-7d4b9c-x2x9q", "namespace": "shop"},
+      "reason": "BackOff",
+      "message": "Back-off restarting failed container",
+      "type": "Warning",
+      "count": 2,
+      "firstTimestamp": "2024-05-02T08:00:00Z",
+      "lastTimestamp": "%s"
+    },
+    {
+      "metadata": {"name": "old.17cc", "namespace": "shop"},
+      "involvedObject": {"kind": "Pod", "name": "old", "namespace": "shop"},
+      "reason": "FailedMount",
+      "message": "MountVolume.SetUp failed",
+      "type": "Warning",
+      "count": 50,
+      "firstTimestamp": "2024-05-01T08:00:00Z",
+      "lastTimestamp": "2024-05-01T09:00:00Z"
+    }
+  ]
+}
+`
@@ -0,0 +1,217 @@
+package kubernetes
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+const (
+	serviceAccountDir   = "/var/run/secrets/kubernetes.io/serviceaccount"
+	serviceAccountToken = serviceAccountDir + "/token"
+	serviceAccountCA    = serviceAccountDir + "/ca.crt"
+)
+
+// kubeconfig is the subset of a kubeconfig file needed to talk to a
+// cluster.  Exec and auth provider plugins are not supported.
+//
+// https://kubernetes.io/docs/concepts/configuration/organize-cluster-access-kubeconfig/
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			TokenFile             string `yaml:"tokenFile"`
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+			Username              string `yaml:"username"`
+			Password              string `yaml:"password"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+}
+
+// restConfig is the resolved configuration to talk to the API server.
+type restConfig struct {
+	common.HTTPConfig
+	tlsConfig *tls.Config
+	// tokenFile is re-read on every request, as service account tokens are
+	// rotated.
+	tokenFile string
+}
+
+func (r *restConfig) client() *http.Client {
+	client := r.HTTPConfig.TLSClient(r.tlsConfig)
+	if r.tokenFile != "" {
+		client.Transport = &tokenFileRoundTripper{r.tokenFile, client.Transport}
+	}
+	return client
+}
+
+// inClusterConfig uses the service account mounted into the pod.
+func inClusterConfig(cfg common.HTTPConfig) (*restConfig, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running inside a cluster, configure either URL or Kubeconfig")
+	}
+
+	pool, err := certPool(serviceAccountCA, "")
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.URL = "https://" + net.JoinHostPort(host, port)
+	return &restConfig{
+		HTTPConfig: cfg,
+		tlsConfig:  &tls.Config{RootCAs: pool},
+		tokenFile:  serviceAccountToken,
+	}, nil
+}
+
+// loadKubeconfig resolves the given context, or the current one, from a
+// kubeconfig file.
+func loadKubeconfig(path, contextName string) (*restConfig, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var kc kubeconfig
+	if err := yaml.Unmarshal(b, &kc); err != nil {
+		return nil, fmt.Errorf("cannot parse kubeconfig %s: %w", path, err)
+	}
+
+	if contextName == "" {
+		contextName = kc.CurrentContext
+	}
+
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == contextName {
+			clusterName, userName, found = c.Context.Cluster, c.Context.User, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("context %q not found in kubeconfig %s", contextName, path)
+	}
+
+	cfg := &restConfig{tlsConfig: &tls.Config{}}
+
+	found = false
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		found = true
+
+		cfg.URL = strings.TrimSuffix(c.Cluster.Server, "/")
+		cfg.Insecure = c.Cluster.InsecureSkipTLSVerify
+		cfg.tlsConfig.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		if c.Cluster.CertificateAuthority != "" || c.Cluster.CertificateAuthorityData != "" {
+			cfg.tlsConfig.RootCAs, err = certPool(c.Cluster.CertificateAuthority, c.Cluster.CertificateAuthorityData)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("cluster %q not found in kubeconfig %s", clusterName, path)
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+
+		cfg.BearerToken = u.User.Token
+		cfg.tokenFile = u.User.TokenFile
+		cfg.Username = u.User.Username
+		cfg.Password = u.User.Password
+
+		if u.User.ClientCertificate != "" || u.User.ClientCertificateData != "" {
+			certPEM, err := readData(u.User.ClientCertificate, u.User.ClientCertificateData)
+			if err != nil {
+				return nil, err
+			}
+			keyPEM, err := readData(u.User.ClientKey, u.User.ClientKeyData)
+			if err != nil {
+				return nil, err
+			}
+			cert, err := tls.X509KeyPair(certPEM, keyPEM)
+			if err != nil {
+				return nil, err
+			}
+			cfg.tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	return cfg, nil
+}
+
+func certPool(file, data string) (*x509.CertPool, error) {
+	pem, err := readData(file, data)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("no certificates found in certificate authority")
+	}
+	return pool, nil
+}
+
+// readData returns either the base64 encoded inline data or the contents of
+// the referenced file.
+func readData(file, data string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	return os.ReadFile(file)
+}
+
+type tokenFileRoundTripper struct {
+	file string
+	rt   http.RoundTripper
+}
+
+func (rt *tokenFileRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := os.ReadFile(rt.file)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	return rt.rt.RoundTrip(req)
+}
@@ -0,0 +1,98 @@
+package livestatus
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// https://docs.checkmk.com/latest/en/livestatus_references.html
+
+var hostColumns = []string{
+	"name",
+	"state",
+	"last_state_change",
+	"acknowledged",
+	"scheduled_downtime_depth",
+	"notifications_enabled",
+	"plugin_output",
+	"groups",
+	"notes_url",
+}
+
+type host struct {
+	Name                   string
+	State                  int
+	LastStateChange        int64
+	Acknowledged           int
+	ScheduledDowntimeDepth int
+	NotificationsEnabled   int
+	PluginOutput           string
+	Groups                 []string
+	NotesURL               string
+}
+
+func (h *host) UnmarshalJSON(b []byte) error {
+	return unmarshalRow(b, &h.Name, &h.State, &h.LastStateChange, &h.Acknowledged, &h.ScheduledDowntimeDepth,
+		&h.NotificationsEnabled, &h.PluginOutput, &h.Groups, &h.NotesURL)
+}
+
+var serviceColumns = []string{
+	"host_name",
+	"description",
+	"display_name",
+	"state",
+	"last_state_change",
+	"acknowledged",
+	"scheduled_downtime_depth",
+	"notifications_enabled",
+	"plugin_output",
+	"groups",
+	"host_groups",
+	"notes_url",
+	"host_state",
+	"host_acknowledged",
+	"host_scheduled_downtime_depth",
+}
+
+type service struct {
+	HostName                   string
+	Description                string
+	DisplayName                string
+	State                      int
+	LastStateChange            int64
+	Acknowledged               int
+	ScheduledDowntimeDepth     int
+	NotificationsEnabled       int
+	PluginOutput               string
+	Groups                     []string
+	HostGroups                 []string
+	NotesURL                   string
+	HostState                  int
+	HostAcknowledged           int
+	HostScheduledDowntimeDepth int
+}
+
+func (s *service) UnmarshalJSON(b []byte) error {
+	return unmarshalRow(b, &s.HostName, &s.Description, &s.DisplayName, &s.State, &s.LastStateChange,
+		&s.Acknowledged, &s.ScheduledDowntimeDepth, &s.NotificationsEnabled, &s.PluginOutput, &s.Groups,
+		&s.HostGroups, &s.NotesURL, &s.HostState, &s.HostAcknowledged, &s.HostScheduledDowntimeDepth)
+}
+
+// unmarshalRow decodes a row of columns, in the order they were queried.
+func unmarshalRow(b []byte, fields ...any) error {
+	var row []json.RawMessage
+	if err := json.Unmarshal(b, &row); err != nil {
+		return err
+	}
+	if len(row) != len(fields) {
+		return fmt.Errorf("expected %d columns, got %d", len(fields), len(row))
+	}
+
+	for i, f := range fields {
+		if err := json.Unmarshal(row[i], f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
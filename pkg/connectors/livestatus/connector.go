@@ -0,0 +1,241 @@
+package livestatus
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+type Connector struct {
+	config Config
+}
+
+type Config struct {
+	Tag string
+	// Socket is the unix socket of livestatus, e.g. /var/cache/naemon/live.
+	Socket string
+	// Address is host:port of livestatus exposed via TCP, e.g. via xinetd.
+	Address string
+	// NagiosURL is the web interface, used for links.
+	NagiosURL string
+	Timeout   time.Duration
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.Socket == "" && cfg.Address == "" {
+		panic(fmt.Errorf("livestatus: either Socket or Address is required"))
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &Connector{*cfg}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	var hosts []host
+	if err := c.query(ctx, "hosts", hostColumns, &hosts); err != nil {
+		return nil, err
+	}
+	var services []service
+	if err := c.query(ctx, "services", serviceColumns, &services); err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, host := range hosts {
+		if host.Acknowledged > 0 {
+			continue
+		} else if host.NotificationsEnabled == 0 {
+			continue
+		} else if host.ScheduledDowntimeDepth > 0 {
+			continue
+		}
+
+		var links []html.HTML
+		if host.NotesURL != "" {
+			links = append(links, html.HTML("<a href=\""+host.NotesURL+"\" target=\"_blank\" alt=\"Runbook\">📖</a>"))
+		}
+		if c.config.NagiosURL != "" {
+			links = append(links, html.HTML("<a href=\""+c.config.NagiosURL+"/cgi-bin/extinfo.cgi?type=1&host="+url.QueryEscape(host.Name)+"\" target=\"_blank\" alt=\"Home\">🏠</a>"))
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Hostname": host.Name,
+				"Source":   c.address(),
+				"groups":   strings.Join(host.Groups, ","),
+				"Type":     "Host",
+			},
+			Start:       time.Unix(host.LastStateChange, 0),
+			State:       fromHostState(host.State),
+			Description: "Host down",
+			Details:     host.PluginOutput,
+			Links:       links,
+		}
+		alert.Silence = c.createSilencer(host.Name, "")
+		alerts = append(alerts, alert)
+	}
+
+	for _, service := range services {
+		// Problems of the host supersede those of its services
+		if service.HostState != 0 || service.HostAcknowledged > 0 || service.HostScheduledDowntimeDepth > 0 {
+			continue
+		} else if service.Acknowledged > 0 {
+			continue
+		} else if service.NotificationsEnabled == 0 {
+			continue
+		} else if service.ScheduledDowntimeDepth > 0 {
+			continue
+		}
+
+		var links []html.HTML
+		if service.NotesURL != "" {
+			links = append(links, html.HTML("<a href=\""+service.NotesURL+"\" target=\"_blank\" alt=\"Runbook\">📖</a>"))
+		}
+		if c.config.NagiosURL != "" {
+			links = append(links, html.HTML("<a href=\""+c.config.NagiosURL+"/cgi-bin/extinfo.cgi?type=2&host="+url.QueryEscape(service.HostName)+"&service="+url.QueryEscape(service.Description)+"\" target=\"_blank\" alt=\"Home\">🏠</a>"))
+		}
+
+		descr := service.DisplayName
+		if descr == "" {
+			descr = service.Description
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Hostname":   service.HostName,
+				"Source":     c.address(),
+				"groups":     strings.Join(service.Groups, ","),
+				"hostgroups": strings.Join(service.HostGroups, ","),
+				"Type":       "Service",
+			},
+			Start:       time.Unix(service.LastStateChange, 0),
+			State:       fromServiceState(service.State),
+			Description: descr,
+			Details:     service.PluginOutput,
+			Links:       links,
+		}
+		alert.Silence = c.createSilencer(service.HostName, service.Description)
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Livestatus (%s)", c.address())
+}
+
+func (c *Connector) address() string {
+	if c.config.Socket != "" {
+		return c.config.Socket
+	}
+	return c.config.Address
+}
+
+func (c *Connector) dial(ctx context.Context) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	var d net.Dialer
+	if c.config.Socket != "" {
+		return d.DialContext(ctx, "unix", c.config.Socket)
+	}
+	return d.DialContext(ctx, "tcp", c.config.Address)
+}
+
+// query gets all objects of the table with a non-OK state.  The fixed16
+// response header contains the status code and the length of the response.
+//
+// see https://docs.checkmk.com/latest/en/livestatus.html
+func (c *Connector) query(ctx context.Context, table string, columns []string, v any) error {
+	slog.DebugContext(ctx, "getting alerts", slog.String("address", c.address()), slog.String("table", table))
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.config.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	q := "GET " + table + "\n" +
+		"Columns: " + strings.Join(columns, " ") + "\n" +
+		"Filter: state != 0\n" +
+		"OutputFormat: json\n" +
+		"ResponseHeader: fixed16\n" +
+		"\n"
+	if _, err := io.WriteString(conn, q); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+	status, err := strconv.Atoi(string(header[0:3]))
+	if err != nil {
+		return fmt.Errorf("invalid response header %q", header)
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(string(header[4:15])))
+	if err != nil {
+		return fmt.Errorf("invalid response header %q", header)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return err
+	}
+
+	if status != 200 {
+		return fmt.Errorf("failed to get %s, status code %d: %s", table, status, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("address", c.address()),
+			slog.String("table", table),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
+
+// see https://assets.nagios.com/downloads/nagioscore/docs/nagioscore/4/en/hostchecks.html
+func fromHostState(state int) connectors.State {
+	switch state {
+	case 0: // UP
+		return connectors.OK
+	case 1, 2: // DOWN, UNREACHABLE
+		return connectors.Critical
+	}
+	return connectors.Unknown
+}
+
+func fromServiceState(state int) connectors.State {
+	return connectors.State(state)
+}
@@ -0,0 +1,137 @@
+package livestatus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+func TestConnector(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "live")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	var mu sync.Mutex
+	var commands []string
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				var request []string
+				r := bufio.NewReader(conn)
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					line = strings.TrimSuffix(line, "\n")
+					if line == "" {
+						break
+					}
+					request = append(request, line)
+				}
+
+				var body string
+				switch {
+				case strings.HasPrefix(request[0], "COMMAND "):
+					mu.Lock()
+					commands = append(commands, request[0])
+					mu.Unlock()
+					return
+				case request[0] == "GET hosts":
+					if !strings.Contains(strings.Join(request, "\n"), "Filter: state != 0") {
+						t.Error("Only problems should be queried", request)
+					}
+					body = mockHosts
+				case request[0] == "GET services":
+					body = mockServices
+				default:
+					body = "Invalid GET request, no such table"
+					_, _ = fmt.Fprintf(conn, "%3d %11d\n%s", 404, len(body), body)
+					return
+				}
+
+				_, _ = fmt.Fprintf(conn, "%3d %11d\n%s", 200, len(body), body)
+			}()
+		}
+	}()
+
+	cfg := Config{
+		Tag:       "test",
+		Socket:    socket,
+		NagiosURL: "https://nagios.example.com/nagios",
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts for unsilenced problems", alerts)
+	}
+
+	if alerts[0].Labels["Hostname"] != "db-1" || alerts[0].State != connectors.Critical || alerts[0].Labels["Type"] != "Host" {
+		t.Error("Down hosts should be critical", alerts[0])
+	}
+	if alerts[0].Start != time.Unix(1714644672, 0) {
+		t.Error("The last state change should be the start", alerts[0].Start)
+	}
+	if alerts[1].Description != "HTTP shop" || alerts[1].State != connectors.Warning || alerts[1].Labels["hostgroups"] != "linux,web" {
+		t.Error("Services should be warnings", alerts[1])
+	}
+
+	if err := alerts[1].Silence(context.Background(), time.Hour, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The command is processed asynchronously
+	for range 100 {
+		mu.Lock()
+		n := len(commands)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(commands) != 1 || !strings.HasSuffix(commands[0], "] ACKNOWLEDGE_SVC_PROBLEM;web-1;http;2;1;0;jdoe;jdoe: silenced via tuwat") {
+		t.Error("Services should be acknowledged via external command", commands)
+	}
+}
+
+const mockHosts = `[
+["db-1",1,1714644672,0,0,1,"CRITICAL - Host Unreachable (10.0.0.2)",["linux"],""],
+["db-2",1,1714644672,1,0,1,"CRITICAL - Host Unreachable (10.0.0.3)",["linux"],""],
+["db-3",1,1714644672,0,1,1,"CRITICAL - Host Unreachable (10.0.0.4)",["linux"],""]
+]
+`
+
+const mockServices = `[
+["web-1","http","HTTP shop",1,1714644600,0,0,1,"HTTP WARNING: HTTP/1.1 200 OK - 3.2 second response time",["http"],["linux","web"],"https://wiki.example.com/http",0,0,0],
+["web-1","disk","Disk /",2,1714644600,1,0,1,"DISK CRITICAL - free space: / 100 MB (1%)",[],["linux","web"],"",0,0,0],
+["web-1","load","Load",1,1714644600,0,0,0,"WARNING - load average: 9.0",[],["linux","web"],"",0,0,0],
+["db-1","mysql","MySQL",2,1714644672,0,0,1,"Can't connect to MySQL server",[],["linux"],"",1,0,0]
+]
+`
@@ -0,0 +1,60 @@
+package livestatus
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/version"
+)
+
+func (c *Connector) createSilencer(host, service string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+
+		return c.Silence(ctx, host, service, duration, user)
+	}
+}
+
+// Silence acknowledges the problem via an external command.  Plain Nagios
+// does not support expiring acknowledgements, the duration is ignored.
+// Livestatus does not answer commands, errors only show in the core's log.
+//
+// see https://assets.nagios.com/downloads/nagioscore/docs/externalcmds/cmdinfo.php?command_id=39
+func (c *Connector) Silence(ctx context.Context, host, service string, _ time.Duration, user string) error {
+	comment := fmt.Sprintf("%s: silenced via %s", user, version.Info.Application)
+
+	// sticky, notify, non-persistent
+	var cmd string
+	if service == "" {
+		cmd = commandArgs("ACKNOWLEDGE_HOST_PROBLEM", host, "2", "1", "0", user, comment)
+	} else {
+		cmd = commandArgs("ACKNOWLEDGE_SVC_PROBLEM", host, service, "2", "1", "0", user, comment)
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(c.config.Timeout)); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(conn, fmt.Sprintf("COMMAND [%d] %s\n\n", time.Now().Unix(), cmd))
+	return err
+}
+
+// commandArgs joins the command with its arguments, which must not contain
+// the separator or line breaks.
+func commandArgs(command string, args ...string) string {
+	r := strings.NewReplacer(";", ",", "\n", " ", "\r", " ")
+	for i, arg := range args {
+		args[i] = r.Replace(arg)
+	}
+	return command + ";" + strings.Join(args, ";")
+}
@@ -0,0 +1,58 @@
+package opsgenie
+
+// https://docs.opsgenie.com/docs/alert-api#list-alerts
+
+type alertsResponse struct {
+	Data   []alert `json:"data"`
+	Paging struct {
+		Next string `json:"next"`
+	} `json:"paging"`
+}
+
+type alert struct {
+	ID             string      `json:"id"`
+	TinyID         string      `json:"tinyId"`
+	Alias          string      `json:"alias"`
+	Message        string      `json:"message"`
+	Status         string      `json:"status"`
+	Acknowledged   bool        `json:"acknowledged"`
+	Snoozed        bool        `json:"snoozed"`
+	Count          int         `json:"count"`
+	CreatedAt      string      `json:"createdAt"`
+	LastOccurredAt string      `json:"lastOccurredAt"`
+	Source         string      `json:"source"`
+	Owner          string      `json:"owner"`
+	Priority       string      `json:"priority"`
+	Tags           []string    `json:"tags"`
+	Responders     []responder `json:"responders"`
+	Integration    struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"integration"`
+}
+
+type responder struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// responderEscalation is a responder type besides team, user and schedule
+const responderEscalation = "escalation"
+
+// https://docs.opsgenie.com/docs/team-api#list-teams
+// https://docs.opsgenie.com/docs/escalation-api#list-escalations
+// https://docs.opsgenie.com/docs/schedule-api#list-schedules
+// https://docs.opsgenie.com/docs/user-api#list-user
+
+type namedResponse struct {
+	Data []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		// FullName is only set for users
+		FullName string `json:"fullName"`
+	} `json:"data"`
+	// Paging is only set for users
+	Paging struct {
+		Next string `json:"next"`
+	} `json:"paging"`
+}
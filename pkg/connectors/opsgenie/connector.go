@@ -0,0 +1,289 @@
+package opsgenie
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+
+	mu           sync.Mutex // Protecting names and namesFetched
+	names        map[string]string
+	namesFetched time.Time
+}
+
+// namesTTL is how long resolved names of responders are cached.
+const namesTTL = time.Hour
+
+type Config struct {
+	Tag string
+	// APIKey is an API integration key, sent as GenieKey.
+	APIKey string
+	// Query selects the alerts, see
+	// https://support.atlassian.com/opsgenie/docs/search-queries-for-alerts/
+	Query string
+	// WebURL is the web interface, e.g. https://example.app.opsgenie.com,
+	// used to link to the alerts.
+	WebURL string
+	// Priorities maps priorities onto `ok`, `warning`, `critical` or
+	// `unknown`.  Alerts with other priorities are warnings.
+	Priorities map[string]string
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.URL == "" {
+		cfg.URL = "https://api.opsgenie.com"
+	}
+	if cfg.Query == "" {
+		cfg.Query = "status: open"
+	}
+	if cfg.Priorities == nil {
+		cfg.Priorities = map[string]string{
+			"P1": "critical",
+			"P2": "critical",
+		}
+	}
+
+	return &Connector{config: *cfg, client: cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	sourceAlerts, err := c.collectAlerts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := c.collectNames(ctx)
+
+	var alerts []connectors.Alert
+	for _, sourceAlert := range sourceAlerts {
+		if sourceAlert.Snoozed {
+			continue
+		}
+
+		state := connectors.Warning
+		if mapped, ok := c.config.Priorities[sourceAlert.Priority]; ok {
+			state = parseState(mapped)
+		}
+
+		var responders, escalations []string
+		for _, r := range sourceAlert.Responders {
+			name := names[r.ID]
+			if name == "" {
+				name = r.ID
+			}
+			if r.Type == responderEscalation {
+				escalations = append(escalations, name)
+			} else {
+				responders = append(responders, name)
+			}
+		}
+
+		status := sourceAlert.Status
+		if sourceAlert.Acknowledged {
+			status = "acknowledged"
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Alias":       sourceAlert.Alias,
+				"Priority":    sourceAlert.Priority,
+				"Status":      status,
+				"Responders":  strings.Join(responders, ","),
+				"Escalation":  strings.Join(escalations, ","),
+				"Owner":       sourceAlert.Owner,
+				"Tags":        strings.Join(sourceAlert.Tags, ","),
+				"Integration": sourceAlert.Integration.Name,
+				"Hostname":    sourceAlert.Source,
+				"Source":      c.config.URL,
+				"Type":        "Alert",
+			},
+			Start:       parseTime(ctx, sourceAlert.CreatedAt),
+			State:       state,
+			Description: sourceAlert.Message,
+			Details:     fmt.Sprintf("#%s, occurred %d times", sourceAlert.TinyID, sourceAlert.Count),
+		}
+		if c.config.WebURL != "" {
+			alert.Links = []html.HTML{
+				html.HTML("<a href=\"" + c.config.WebURL + "/alert/detail/" + url.PathEscape(sourceAlert.ID) + "/details\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			}
+		}
+		// Acknowledged alerts are already being handled by on-call
+		if !sourceAlert.Acknowledged {
+			alert.Silence = c.createSilencer(sourceAlert.ID)
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Opsgenie (%s)", c.config.URL)
+}
+
+// collectAlerts gets all alerts matching the query, following the pagination.
+func (c *Connector) collectAlerts(ctx context.Context) ([]alert, error) {
+	const limit = 100
+
+	query := url.Values{
+		"query": {c.config.Query},
+		"limit": {strconv.Itoa(limit)},
+		"sort":  {"createdAt"},
+		"order": {"desc"},
+	}
+
+	var alerts []alert
+	// Opsgenie only allows paging up to an offset of 20000
+	for offset := 0; offset < 20000; offset += limit {
+		query.Set("offset", strconv.Itoa(offset))
+
+		var response alertsResponse
+		if err := c.get(ctx, "/v2/alerts", query, &response); err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, response.Data...)
+
+		if response.Paging.Next == "" || len(response.Data) < limit {
+			break
+		}
+	}
+
+	return alerts, nil
+}
+
+// collectNames resolves the ids of responders to their names.  Names are
+// cached and only resolved on a best-effort basis, as API keys often lack the
+// configuration access needed.  Unresolved responders are shown by their id.
+func (c *Connector) collectNames(ctx context.Context) map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Since(c.namesFetched) < namesTTL {
+		return c.names
+	}
+
+	// Keep previously resolved names, if an endpoint fails temporarily
+	names := maps.Clone(c.names)
+	if names == nil {
+		names = make(map[string]string)
+	}
+	for _, endpoint := range []string{"/v2/teams", "/v2/escalations", "/v2/schedules", "/v2/users"} {
+		if err := c.collectNamesFrom(ctx, endpoint, names); err != nil {
+			slog.WarnContext(ctx, "Cannot resolve names",
+				slog.String("url", c.config.URL+endpoint),
+				slog.Any("error", err))
+		}
+	}
+
+	c.names = names
+	c.namesFetched = time.Now()
+
+	return names
+}
+
+// collectNamesFrom adds the names of the endpoint, following the pagination
+// of the users.
+func (c *Connector) collectNamesFrom(ctx context.Context, endpoint string, names map[string]string) error {
+	const limit = 500
+
+	query := url.Values{"limit": {strconv.Itoa(limit)}}
+	for offset := 0; ; offset += limit {
+		query.Set("offset", strconv.Itoa(offset))
+
+		var response namedResponse
+		if err := c.get(ctx, endpoint, query, &response); err != nil {
+			return err
+		}
+		for _, d := range response.Data {
+			names[d.ID] = d.Name
+			if d.FullName != "" {
+				names[d.ID] = d.FullName
+			}
+		}
+
+		if response.Paging.Next == "" || len(response.Data) < limit {
+			return nil
+		}
+	}
+}
+
+func parseState(state string) connectors.State {
+	switch strings.ToLower(state) {
+	case "ok":
+		return connectors.OK
+	case "warning":
+		return connectors.Warning
+	case "critical":
+		return connectors.Critical
+	}
+	return connectors.Unknown
+}
+
+func parseTime(ctx context.Context, s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse", slog.String("time", s), slog.Any("error", err))
+		return time.Time{}
+	}
+	return t
+}
+
+func (c *Connector) get(ctx context.Context, endpoint string, query url.Values, v any) error {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+c.config.APIKey)
+	req.URL.RawQuery = query.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+endpoint),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package opsgenie
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+func TestConnector(t *testing.T) {
+	var acknowledged map[string]string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "GenieKey example-key" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body string
+		switch req.URL.Path {
+		case "/v2/alerts":
+			if req.URL.Query().Get("query") != "status: open" {
+				t.Error("Open alerts should be queried by default", req.URL)
+			}
+			body = mockAlerts
+		case "/v2/teams":
+			body = `{"data": [{"id": "team-1", "name": "ops"}]}`
+		case "/v2/escalations":
+			body = `{"data": [{"id": "esc-1", "name": "ops_escalation"}]}`
+		case "/v2/schedules":
+			body = `{"data": []}`
+		case "/v2/users":
+			body = `{"data": [{"id": "user-1", "username": "jane@example.com", "fullName": "Jane Doe"}]}`
+		case "/v2/alerts/a1/acknowledge":
+			if req.Method != http.MethodPost || req.URL.Query().Get("identifierType") != "id" {
+				t.Error("Alerts should be acknowledged by id", req.Method, req.URL)
+			}
+			_ = json.NewDecoder(req.Body).Decode(&acknowledged)
+			res.WriteHeader(http.StatusAccepted)
+			_, _ = res.Write([]byte(`{"result": "Request will be processed"}`))
+			return
+		default:
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:    "test",
+		APIKey: "example-key",
+		WebURL: "https://example.app.opsgenie.com",
+	}
+	cfg.URL = mockServer.URL
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts for open alerts which are not snoozed", alerts)
+	}
+
+	if alerts[0].State != connectors.Critical || alerts[0].Labels["Priority"] != "P1" || alerts[0].Labels["Status"] != "open" {
+		t.Error("P1 alerts should be critical", alerts[0])
+	}
+	if alerts[0].Labels["Responders"] != "ops,Jane Doe" || alerts[0].Labels["Escalation"] != "ops_escalation" {
+		t.Error("Responders should be resolved by name", alerts[0].Labels)
+	}
+	if alerts[0].Silence == nil {
+		t.Error("Unacknowledged alerts should be silenceable", alerts[0])
+	}
+	if alerts[1].State != connectors.Warning || alerts[1].Labels["Status"] != "acknowledged" || alerts[1].Silence != nil {
+		t.Error("Acknowledged alerts should be shown as such", alerts[1])
+	}
+
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jane@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if acknowledged["user"] != "jane@example.com" {
+		t.Error("The alert should be acknowledged by the user", acknowledged)
+	}
+}
+
+func TestNames(t *testing.T) {
+	var requests atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/v2/alerts":
+			_, _ = res.Write([]byte(mockAlerts))
+		case "/v2/teams":
+			requests.Add(1)
+			res.WriteHeader(http.StatusForbidden)
+			_, _ = res.Write([]byte(`{"message": "Key has no access to configuration"}`))
+		case "/v2/users":
+			requests.Add(1)
+			if req.URL.Query().Get("offset") != "0" {
+				_, _ = res.Write([]byte(`{"data": [{"id": "user-1", "fullName": "Jane Doe"}]}`))
+				return
+			}
+			var users []string
+			for i := range 500 {
+				users = append(users, fmt.Sprintf(`{"id": "other-%d", "fullName": "Other %d"}`, i, i))
+			}
+			_, _ = res.Write([]byte(`{"data": [` + strings.Join(users, ",") + `], "paging": {"next": "https://api.opsgenie.com/v2/users?offset=500"}}`))
+		default:
+			requests.Add(1)
+			_, _ = res.Write([]byte(`{"data": []}`))
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{Tag: "test"}
+	cfg.URL = mockServer.URL
+	connector := NewConnector(&cfg)
+
+	for range 2 {
+		alerts, err := connector.Collect(context.Background())
+		if err != nil {
+			t.Fatal("Failing to resolve names should not fail the collection", err)
+		}
+		if alerts[0].Labels["Responders"] != "team-1,Jane Doe" {
+			t.Error("Users should be paged and unresolved responders shown by id", alerts[0].Labels)
+		}
+	}
+
+	if requests.Load() != 5 {
+		t.Error("Names should be cached", requests.Load())
+	}
+}
+
+const mockAlerts = `
+{
+  "data": [
+    {
+      "id": "a1", "tinyId": "42", "alias": "db-down", "message": "Database down",
+      "status": "open", "acknowledged": false, "snoozed": false, "count": 3,
+      "createdAt": "2024-05-02T10:11:12.197Z", "source": "db-1", "priority": "P1", "tags": ["db"],
+      "responders": [{"type": "team", "id": "team-1"}, {"type": "user", "id": "user-1"}, {"type": "escalation", "id": "esc-1"}],
+      "integration": {"name": "Prometheus", "type": "Prometheus"}
+    },
+    {
+      "id": "a2", "tinyId": "43", "message": "Disk filling up",
+      "status": "open", "acknowledged": true, "snoozed": false, "count": 1,
+      "createdAt": "2024-05-02T11:11:12.197Z", "priority": "P3",
+      "responders": [{"type": "team", "id": "team-1"}]
+    },
+    {
+      "id": "a3", "tinyId": "44", "message": "Certificate expiring",
+      "status": "open", "acknowledged": false, "snoozed": true, "count": 1,
+      "createdAt": "2024-05-02T12:11:12.197Z", "priority": "P2"
+    }
+  ],
+  "paging": {"first": "https://api.opsgenie.com/v2/alerts?offset=0", "last": "https://api.opsgenie.com/v2/alerts?offset=0"}
+}`
@@ -0,0 +1,61 @@
+package opsgenie
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+func (c *Connector) createSilencer(alertID string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+
+		return c.Silence(ctx, alertID, duration, user)
+	}
+}
+
+// Silence acknowledges the alert.  An acknowledgement does not expire, the
+// duration is ignored.
+//
+// see https://docs.opsgenie.com/docs/alert-api#acknowledge-alert
+func (c *Connector) Silence(ctx context.Context, alertID string, _ time.Duration, user string) error {
+	payload := map[string]interface{}{
+		"user":   user,
+		"source": "tuwat",
+		"note":   "Acknowledged via tuwat",
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		return err
+	}
+
+	endpoint := "/v2/alerts/" + url.PathEscape(alertID) + "/acknowledge?identifierType=id"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL+endpoint, buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+c.config.APIKey)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to acknowledge alert, status code %d: %s", res.StatusCode, string(b))
+	}
+
+	return nil
+}
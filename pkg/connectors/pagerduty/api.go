@@ -0,0 +1,52 @@
+package pagerduty
+
+// https://developer.pagerduty.com/api-reference/9d0b4b12e36f9-list-incidents
+
+type incidentsResponse struct {
+	Incidents []incident `json:"incidents"`
+	Limit     int        `json:"limit"`
+	Offset    int        `json:"offset"`
+	More      bool       `json:"more"`
+}
+
+type incident struct {
+	ID               string           `json:"id"`
+	IncidentNumber   int              `json:"incident_number"`
+	Title            string           `json:"title"`
+	Description      string           `json:"description"`
+	CreatedAt        string           `json:"created_at"`
+	Status           string           `json:"status"`
+	Urgency          string           `json:"urgency"`
+	HTMLURL          string           `json:"html_url"`
+	Service          reference        `json:"service"`
+	EscalationPolicy reference        `json:"escalation_policy"`
+	Priority         *reference       `json:"priority"`
+	Teams            []reference      `json:"teams"`
+	Assignments      []assignment     `json:"assignments"`
+	Acknowledgements []acknowledgment `json:"acknowledgements"`
+}
+
+// reference is a summarized reference to another object.
+type reference struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Summary string `json:"summary"`
+	HTMLURL string `json:"html_url"`
+}
+
+type assignment struct {
+	At       string    `json:"at"`
+	Assignee reference `json:"assignee"`
+}
+
+type acknowledgment struct {
+	At           string    `json:"at"`
+	Acknowledger reference `json:"acknowledger"`
+}
+
+const (
+	statusTriggered    = "triggered"
+	statusAcknowledged = "acknowledged"
+
+	urgencyHigh = "high"
+)
@@ -0,0 +1,236 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	Tag string
+	// Token is a REST API key, sent as `Token token=...`.
+	Token string
+	// From is the email address of a PagerDuty user to acknowledge incidents
+	// as, if the silencing user is not known to PagerDuty by email.
+	From     string
+	Services []string
+	Teams    []string
+	// Statuses of incidents to show, defaults to triggered and acknowledged
+	// incidents, showing what is already being handled.
+	Statuses []string
+	// Priorities maps priority names onto `ok`, `warning`, `critical` or
+	// `unknown`.  Without priority, high urgency incidents are critical and
+	// low urgency incidents are warnings.
+	Priorities map[string]string
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.URL == "" {
+		cfg.URL = "https://api.pagerduty.com"
+	}
+	if len(cfg.Statuses) == 0 {
+		cfg.Statuses = []string{statusTriggered, statusAcknowledged}
+	}
+	if cfg.Priorities == nil {
+		cfg.Priorities = map[string]string{
+			"P1": "critical",
+			"P2": "critical",
+		}
+	}
+
+	return &Connector{*cfg, cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	incidents, err := c.collectIncidents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, inc := range incidents {
+		var assignees []string
+		for _, a := range inc.Assignments {
+			assignees = append(assignees, a.Assignee.Summary)
+		}
+		var teams []string
+		for _, t := range inc.Teams {
+			teams = append(teams, t.Summary)
+		}
+
+		var details []string
+		if inc.Description != "" && inc.Description != inc.Title {
+			details = append(details, inc.Description)
+		}
+		for _, a := range inc.Acknowledgements {
+			details = append(details, "Acknowledged by "+a.Acknowledger.Summary)
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Incident":   strconv.Itoa(inc.IncidentNumber),
+				"Service":    inc.Service.Summary,
+				"Priority":   priorityName(inc),
+				"Urgency":    inc.Urgency,
+				"Status":     inc.Status,
+				"Responders": strings.Join(assignees, ","),
+				"Escalation": inc.EscalationPolicy.Summary,
+				"Teams":      strings.Join(teams, ","),
+				"Source":     c.config.URL,
+				"Type":       "Incident",
+			},
+			Start:       parseTime(ctx, inc.CreatedAt),
+			State:       c.state(inc),
+			Description: inc.Title,
+			Details:     strings.Join(details, "\n"),
+			Links: []html.HTML{
+				html.HTML("<a href=\"" + inc.HTMLURL + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			},
+		}
+		// Acknowledged incidents are already being handled by on-call
+		if inc.Status == statusTriggered {
+			alert.Silence = c.createSilencer(inc.ID)
+		}
+
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("PagerDuty (%s)", c.config.URL)
+}
+
+func (c *Connector) state(inc incident) connectors.State {
+	if inc.Priority != nil {
+		if mapped, ok := c.config.Priorities[inc.Priority.Summary]; ok {
+			return parseState(mapped)
+		}
+	}
+	if inc.Urgency == urgencyHigh {
+		return connectors.Critical
+	}
+	return connectors.Warning
+}
+
+func priorityName(inc incident) string {
+	if inc.Priority == nil {
+		return ""
+	}
+	return inc.Priority.Summary
+}
+
+// collectIncidents gets all incidents, following the pagination.
+func (c *Connector) collectIncidents(ctx context.Context) ([]incident, error) {
+	const limit = 100
+
+	query := url.Values{
+		"limit":      {strconv.Itoa(limit)},
+		"statuses[]": c.config.Statuses,
+		"sort_by":    {"created_at:desc"},
+	}
+	if len(c.config.Services) > 0 {
+		query["service_ids[]"] = c.config.Services
+	}
+	if len(c.config.Teams) > 0 {
+		query["team_ids[]"] = c.config.Teams
+	}
+
+	var incidents []incident
+	// PagerDuty only allows classic pagination up to an offset of 10000
+	for offset := 0; offset < 10000; offset += limit {
+		query.Set("offset", strconv.Itoa(offset))
+
+		var response incidentsResponse
+		if err := c.get(ctx, "/incidents", query, &response); err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, response.Incidents...)
+
+		if !response.More {
+			break
+		}
+	}
+
+	return incidents, nil
+}
+
+func parseState(state string) connectors.State {
+	switch strings.ToLower(state) {
+	case "ok":
+		return connectors.OK
+	case "warning":
+		return connectors.Warning
+	case "critical":
+		return connectors.Critical
+	}
+	return connectors.Unknown
+}
+
+func parseTime(ctx context.Context, s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse", slog.String("time", s), slog.Any("error", err))
+		return time.Time{}
+	}
+	return t
+}
+
+func (c *Connector) get(ctx context.Context, endpoint string, query url.Values, v any) error {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", "Token token="+c.config.Token)
+	req.URL.RawQuery = query.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+endpoint),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
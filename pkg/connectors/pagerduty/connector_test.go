@@ -0,0 +1,117 @@
+package pagerduty
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+func TestConnector(t *testing.T) {
+	var acknowledgedBy string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Token token=example-token" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body string
+		switch req.URL.Path {
+		case "/incidents":
+			if !slices.Equal(req.URL.Query()["statuses[]"], []string{"triggered", "acknowledged"}) {
+				t.Error("Triggered and acknowledged incidents should be shown", req.URL)
+			}
+			if !slices.Equal(req.URL.Query()["service_ids[]"], []string{"PSVC1"}) {
+				t.Error("Incidents should be filtered by service", req.URL)
+			}
+			if req.URL.Query().Get("offset") == "0" {
+				body = `{"incidents": [` + mockIncidentTriggered + `], "limit": 1, "offset": 0, "more": true}`
+			} else {
+				body = `{"incidents": [` + mockIncidentAcknowledged + `], "limit": 1, "offset": 100, "more": false}`
+			}
+		case "/incidents/PINC1":
+			if req.Method != http.MethodPut {
+				t.Error("Incidents should be updated", req.Method)
+			}
+			acknowledgedBy = req.Header.Get("From")
+			body = `{"incident": {"id": "PINC1", "status": "acknowledged"}}`
+		default:
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:      "test",
+		Token:    "example-token",
+		From:     "oncall@example.com",
+		Services: []string{"PSVC1"},
+	}
+	cfg.URL = mockServer.URL
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts for all incidents", alerts)
+	}
+
+	if alerts[0].State != connectors.Critical || alerts[0].Labels["Priority"] != "P1" || alerts[0].Labels["Status"] != "triggered" {
+		t.Error("P1 incidents should be critical", alerts[0])
+	}
+	if alerts[0].Labels["Responders"] != "Jane Doe" || alerts[0].Labels["Escalation"] != "Ops Escalation" || alerts[0].Labels["Service"] != "Shop" {
+		t.Error("Responders and escalation policy should be labels", alerts[0].Labels)
+	}
+	if alerts[0].Silence == nil {
+		t.Error("Triggered incidents should be silenceable", alerts[0])
+	}
+	if alerts[1].State != connectors.Warning || alerts[1].Labels["Status"] != "acknowledged" || alerts[1].Silence != nil {
+		t.Error("Acknowledged low urgency incidents should be shown as such", alerts[1])
+	}
+	if alerts[1].Details != "Acknowledged by John Doe" {
+		t.Error("The acknowledgers should be the details", alerts[1].Details)
+	}
+
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jdoe"); err != nil {
+		t.Fatal(err)
+	}
+	if acknowledgedBy != "oncall@example.com" {
+		t.Error("Without email, the configured user should acknowledge", acknowledgedBy)
+	}
+}
+
+const mockIncidentTriggered = `
+{
+  "id": "PINC1", "incident_number": 1234, "title": "Checkout failing", "description": "Checkout failing",
+  "created_at": "2024-05-02T10:11:12Z", "status": "triggered", "urgency": "high",
+  "html_url": "https://example.pagerduty.com/incidents/PINC1",
+  "service": {"id": "PSVC1", "type": "service_reference", "summary": "Shop"},
+  "escalation_policy": {"id": "PESC1", "type": "escalation_policy_reference", "summary": "Ops Escalation"},
+  "priority": {"id": "PPRI1", "type": "priority", "summary": "P1"},
+  "teams": [{"id": "PTEAM1", "type": "team_reference", "summary": "Ops"}],
+  "assignments": [{"at": "2024-05-02T10:11:12Z", "assignee": {"id": "PUSR1", "type": "user_reference", "summary": "Jane Doe"}}],
+  "acknowledgements": []
+}`
+
+const mockIncidentAcknowledged = `
+{
+  "id": "PINC2", "incident_number": 1235, "title": "Disk filling up",
+  "created_at": "2024-05-02T11:11:12Z", "status": "acknowledged", "urgency": "low",
+  "html_url": "https://example.pagerduty.com/incidents/PINC2",
+  "service": {"id": "PSVC1", "type": "service_reference", "summary": "Shop"},
+  "escalation_policy": {"id": "PESC1", "type": "escalation_policy_reference", "summary": "Ops Escalation"},
+  "priority": null,
+  "assignments": [{"at": "2024-05-02T11:11:12Z", "assignee": {"id": "PUSR2", "type": "user_reference", "summary": "John Doe"}}],
+  "acknowledgements": [{"at": "2024-05-02T11:15:00Z", "acknowledger": {"id": "PUSR2", "type": "user_reference", "summary": "John Doe"}}]
+}`
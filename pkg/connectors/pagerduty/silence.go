@@ -0,0 +1,72 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+func (c *Connector) createSilencer(incidentID string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+
+		return c.Silence(ctx, incidentID, duration, user)
+	}
+}
+
+// Silence acknowledges the incident.  PagerDuty requires the email address of
+// a user acknowledging the incident, the configured From address is used if
+// the user is no email address.  The duration is ignored, acknowledgements
+// time out according to the service settings.
+//
+// see https://developer.pagerduty.com/api-reference/8a0e1aa2ec666-update-an-incident
+func (c *Connector) Silence(ctx context.Context, incidentID string, _ time.Duration, user string) error {
+	from := c.config.From
+	if strings.Contains(user, "@") {
+		from = user
+	}
+
+	payload := map[string]interface{}{
+		"incident": map[string]interface{}{
+			"type":   "incident_reference",
+			"status": statusAcknowledged,
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		return err
+	}
+
+	endpoint := "/incidents/" + url.PathEscape(incidentID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.config.URL+endpoint, buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	req.Header.Set("Authorization", "Token token="+c.config.Token)
+	req.Header.Set("From", from)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to acknowledge incident, status code %d: %s", res.StatusCode, string(b))
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+// ProtocolVersion is the version of the plugin protocol spoken by tuwat.
+// Plugins reporting a different version during `initialize` are rejected.
+const ProtocolVersion = 1
+
+const jsonRPCVersion = "2.0"
+
+const (
+	methodInitialize = "initialize"
+	methodCollect    = "collect"
+	methodSilence    = "silence"
+)
+
+// JSON-RPC 2.0 error codes, see https://www.jsonrpc.org/specification#error_object
+const (
+	ErrorParse          = -32700
+	ErrorMethodNotFound = -32601
+	ErrorInvalidParams  = -32602
+	ErrorInternal       = -32603
+)
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      uint64          `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object returned by a plugin.
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+type InitializeParams struct {
+	ProtocolVersion int `json:"protocolVersion"`
+}
+
+type InitializeResult struct {
+	ProtocolVersion int    `json:"protocolVersion"`
+	Name            string `json:"name"`
+}
+
+type CollectResult struct {
+	Alerts []Alert `json:"alerts"`
+}
+
+// Alert mirrors connectors.Alert on the wire.  If ID is set, the alert can
+// be silenced and the ID is handed back to the plugin in `silence`.
+type Alert struct {
+	ID          string            `json:"id,omitempty"`
+	Labels      map[string]string `json:"labels"`
+	Start       time.Time         `json:"start"`
+	State       connectors.State  `json:"state"`
+	Description string            `json:"description"`
+	Details     string            `json:"details"`
+	Links       []Link            `json:"links,omitempty"`
+}
+
+type Link struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+}
+
+type SilenceParams struct {
+	ID string `json:"id"`
+	// Duration of the silence in seconds.
+	Duration int64  `json:"duration"`
+	User     string `json:"user"`
+}
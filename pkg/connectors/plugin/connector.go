@@ -0,0 +1,235 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	html "html/template"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+// errCallTimeout distinguishes a hung plugin from a canceled call.
+var errCallTimeout = errors.New("plugin call timed out")
+
+type Connector struct {
+	config Config
+
+	mu        sync.Mutex // Protecting proc, failures and notBefore
+	proc      *process
+	failures  int
+	notBefore time.Time
+
+	nextID atomic.Uint64
+}
+
+type Config struct {
+	Tag     string
+	Command string
+	Args    []string
+	// Env contains additional environment variables in the form `KEY=value`.
+	Env []string
+	Dir string
+	// Timeout for a single call to the plugin.
+	Timeout time.Duration
+	// MaxBackoff limits the delay between restarts of a failing plugin.
+	MaxBackoff time.Duration
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 20 * time.Second
+	}
+	if cfg.MaxBackoff == 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+
+	return &Connector{config: *cfg}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	var result CollectResult
+	if err := c.call(ctx, methodCollect, nil, &result); err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, sourceAlert := range result.Alerts {
+		var links []html.HTML
+		for _, link := range sourceAlert.Links {
+			title := link.Title
+			if title == "" {
+				title = "🏠"
+			}
+			links = append(links, html.HTML("<a href=\""+html.HTMLEscapeString(link.URL)+"\" target=\"_blank\" alt=\"Home\">"+html.HTMLEscapeString(title)+"</a>"))
+		}
+
+		labels := map[string]string{
+			"Source": c.config.Command,
+		}
+		for k, v := range sourceAlert.Labels {
+			labels[k] = v
+		}
+
+		alert := connectors.Alert{
+			Labels:      labels,
+			Start:       sourceAlert.Start,
+			State:       sourceAlert.State,
+			Description: sourceAlert.Description,
+			Details:     sourceAlert.Details,
+			Links:       links,
+		}
+		if sourceAlert.ID != "" {
+			alert.Silence = c.createSilencer(sourceAlert.ID)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Plugin (%s)", c.config.Command)
+}
+
+// Close stops the plugin process, if it is running.
+func (c *Connector) Close() error {
+	c.mu.Lock()
+	p := c.proc
+	c.proc = nil
+	c.mu.Unlock()
+
+	if p != nil {
+		p.kill()
+	}
+	return nil
+}
+
+func (c *Connector) createSilencer(id string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+
+		params := SilenceParams{
+			ID:       id,
+			Duration: int64(duration / time.Second),
+			User:     user,
+		}
+		return c.call(ctx, methodSilence, params, nil)
+	}
+}
+
+// call sends a request to the plugin, starting it if needed.  A call which
+// does not return within the configured timeout kills the plugin, as it is
+// considered to be hung.  If the caller gives up first, only the response is
+// abandoned.
+func (c *Connector) call(ctx context.Context, method string, params, result any) error {
+	p, err := c.process(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, c.config.Timeout, errCallTimeout)
+	defer cancel()
+
+	err = p.call(ctx, c.nextID.Add(1), method, params, result)
+	if err != nil && errors.Is(context.Cause(ctx), errCallTimeout) {
+		slog.WarnContext(ctx, "plugin call timed out, killing plugin",
+			slog.String("plugin", c.config.Tag),
+			slog.String("method", method))
+		c.fail(p)
+		p.kill()
+	} else if err == nil {
+		c.mu.Lock()
+		c.failures = 0
+		c.mu.Unlock()
+	}
+
+	return err
+}
+
+// process returns the running plugin process, restarting it if it exited and
+// the backoff delay has passed.
+func (c *Connector) process(ctx context.Context) (*process, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.proc != nil && !c.proc.exited() {
+		return c.proc, nil
+	} else if c.proc != nil {
+		c.failLocked(c.proc)
+	}
+
+	if wait := time.Until(c.notBefore); wait > 0 {
+		return nil, fmt.Errorf("plugin %s failed %d times, restarting in %s", c.config.Command, c.failures, wait.Round(time.Second))
+	}
+
+	slog.InfoContext(ctx, "starting plugin",
+		slog.String("plugin", c.config.Tag),
+		slog.String("command", c.config.Command),
+		slog.String("args", strings.Join(c.config.Args, " ")))
+
+	p, err := startProcess(c.config)
+	if err != nil {
+		c.backoffLocked()
+		return nil, err
+	}
+
+	initCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	var result InitializeResult
+	err = p.call(initCtx, c.nextID.Add(1), methodInitialize, InitializeParams{ProtocolVersion: ProtocolVersion}, &result)
+	if err == nil && result.ProtocolVersion != ProtocolVersion {
+		err = fmt.Errorf("plugin %s speaks protocol version %d, expected %d", c.config.Command, result.ProtocolVersion, ProtocolVersion)
+	}
+	if err != nil {
+		p.kill()
+		if ctx.Err() == nil {
+			// Only failures of the plugin delay the next start
+			c.backoffLocked()
+		}
+		return nil, err
+	}
+
+	slog.InfoContext(ctx, "plugin started",
+		slog.String("plugin", c.config.Tag),
+		slog.String("name", result.Name))
+
+	c.proc = p
+	return p, nil
+}
+
+func (c *Connector) fail(p *process) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failLocked(p)
+}
+
+func (c *Connector) failLocked(p *process) {
+	if c.proc != p {
+		// already handled
+		return
+	}
+	c.proc = nil
+	c.backoffLocked()
+}
+
+// backoffLocked doubles the delay before the next start attempt with every
+// consecutive failure, up to MaxBackoff.
+func (c *Connector) backoffLocked() {
+	delay := time.Second << min(c.failures, 16)
+	if delay > c.config.MaxBackoff {
+		delay = c.config.MaxBackoff
+	}
+	c.failures++
+	c.notBefore = time.Now().Add(delay)
+}
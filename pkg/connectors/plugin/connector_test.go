@@ -0,0 +1,149 @@
+package plugin
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+// TestMain lets the test binary act as a plugin, if started by a test.
+func TestMain(m *testing.M) {
+	switch os.Getenv("TUWAT_PLUGIN_TEST") {
+	case "serve":
+		_ = Serve(context.Background(), "test", &testHandler{}, os.Stdin, os.Stdout)
+		os.Exit(0)
+	case "slow":
+		_ = Serve(context.Background(), "test", &testHandler{slow: true}, os.Stdin, os.Stdout)
+		os.Exit(0)
+	case "crash":
+		_, _ = os.Stderr.WriteString("crashing\n")
+		os.Exit(1)
+	case "hang":
+		time.Sleep(time.Minute)
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+type testHandler struct {
+	slow bool
+}
+
+func (h *testHandler) Collect(_ context.Context) ([]Alert, error) {
+	if h.slow {
+		time.Sleep(time.Minute)
+	}
+	return []Alert{
+		{
+			ID:          "1",
+			Labels:      map[string]string{"Hostname": "example.com"},
+			Start:       time.Now(),
+			State:       connectors.Critical,
+			Description: "Disk full",
+			Details:     "/var is at 100%",
+			Links:       []Link{{URL: "https://example.com/?a=b&c=d"}},
+		},
+		{
+			Labels:      map[string]string{"Hostname": "example.org"},
+			State:       connectors.Warning,
+			Description: "Backup old",
+		},
+	}, nil
+}
+
+func (h *testHandler) Silence(_ context.Context, id string, duration time.Duration, user string) error {
+	if id != "1" || duration != time.Hour || user != "jo" {
+		return &Error{Code: ErrorInvalidParams, Message: "unexpected silence"}
+	}
+	return nil
+}
+
+func testConnector(t *testing.T, mode string) *Connector {
+	executable, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewConnector(&Config{
+		Tag:     "test",
+		Command: executable,
+		Env:     []string{"TUWAT_PLUGIN_TEST=" + mode},
+		Timeout: 2 * time.Second,
+	})
+	t.Cleanup(func() { _ = c.Close() })
+
+	return c
+}
+
+func TestConnector(t *testing.T) {
+	connector := testConnector(t, "serve")
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts")
+	}
+	if alerts[0].State != connectors.Critical || alerts[0].Labels["Hostname"] != "example.com" {
+		t.Error("Alert should be mapped", alerts[0])
+	}
+	if !strings.Contains(string(alerts[0].Links[0]), "a=b&amp;c=d") {
+		t.Error("Links should be escaped", alerts[0].Links)
+	}
+	if alerts[0].Silence == nil || alerts[1].Silence != nil {
+		t.Error("Only alerts with an id should be silenceable")
+	}
+
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jo"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRestartBackoff(t *testing.T) {
+	connector := testConnector(t, "crash")
+
+	if _, err := connector.Collect(context.Background()); err == nil {
+		t.Fatal("Collection should fail")
+	}
+
+	_, err := connector.Collect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "restarting in") {
+		t.Error("Restart should be delayed", err)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	connector := testConnector(t, "hang")
+	connector.config.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	if _, err := connector.Collect(context.Background()); err == nil {
+		t.Fatal("Collection should time out")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Timeout should be honored")
+	}
+}
+
+func TestCanceledCall(t *testing.T) {
+	connector := testConnector(t, "slow")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := connector.Collect(ctx); err == nil {
+		t.Fatal("Collection should be canceled")
+	}
+
+	connector.mu.Lock()
+	defer connector.mu.Unlock()
+	if connector.proc == nil || connector.proc.exited() || connector.failures != 0 {
+		t.Error("A canceled call should not restart the plugin")
+	}
+}
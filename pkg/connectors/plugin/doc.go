@@ -0,0 +1,19 @@
+/*
+Package plugin runs connectors as external executables.
+
+The plugin is started by tuwat and speaks JSON-RPC 2.0 over stdin/stdout,
+one JSON document per line.  Anything written to stderr is forwarded into
+the log.  The following methods are called by tuwat:
+
+  - `initialize` with `{"protocolVersion": 1}`, which has to be answered with
+    `{"protocolVersion": 1, "name": "..."}`
+  - `collect` without parameters, answered with `{"alerts": [...]}`, see Alert
+  - `silence` with `{"id": "...", "duration": 86400, "user": "..."}`, only for
+    alerts which carried an `id`
+
+A plugin which exits or does not answer within the configured timeout is
+restarted with an exponential backoff.
+
+See pkg/connectors/example/plugin for a reference implementation in Go.
+*/
+package plugin
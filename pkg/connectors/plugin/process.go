@@ -0,0 +1,211 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+var errExited = errors.New("plugin exited")
+
+// process is a single running instance of a plugin executable.  Requests are
+// written as one JSON document per line to stdin, responses are read the
+// same way from stdout and dispatched by their id.
+type process struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	wmu sync.Mutex // Protecting stdin
+
+	pmu     sync.Mutex // Protecting pending
+	pending map[uint64]chan response
+
+	done chan struct{}
+	err  error
+}
+
+func startProcess(cfg Config) (*process, error) {
+	cmd := exec.Command(cfg.Command, cfg.Args...)
+	cmd.Dir = cfg.Dir
+	cmd.Env = append(os.Environ(), cfg.Env...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &process{
+		cmd:     cmd,
+		stdin:   stdin,
+		pending: make(map[uint64]chan response),
+		done:    make(chan struct{}),
+	}
+
+	logger := slog.With(slog.String("plugin", cfg.Tag), slog.Int("pid", cmd.Process.Pid))
+
+	var wg sync.WaitGroup
+	wg.Go(func() { p.forwardStderr(logger, stderr) })
+	wg.Go(func() { p.readResponses(logger, stdout) })
+
+	go func() {
+		// Wait must only be called after all reads from the pipes are done.
+		wg.Wait()
+		err := cmd.Wait()
+		if err == nil {
+			err = errExited
+		}
+		logger.Info("plugin exited", slog.Any("error", err))
+
+		p.pmu.Lock()
+		p.err = err
+		for id, r := range p.pending {
+			close(r)
+			delete(p.pending, id)
+		}
+		p.pmu.Unlock()
+
+		close(p.done)
+	}()
+
+	return p, nil
+}
+
+func (p *process) forwardStderr(logger *slog.Logger, stderr io.Reader) {
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		logger.Info(scanner.Text())
+	}
+}
+
+func (p *process) readResponses(logger *slog.Logger, stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var res response
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			logger.Error("Cannot parse",
+				slog.String("data", scanner.Text()),
+				slog.Any("error", err))
+			continue
+		}
+
+		p.pmu.Lock()
+		r, ok := p.pending[res.ID]
+		delete(p.pending, res.ID)
+		p.pmu.Unlock()
+
+		if !ok {
+			logger.Warn("discarding unexpected response", slog.Any("id", res.ID))
+			continue
+		}
+		r <- res
+	}
+
+	// Make sure a plugin closing stdout without exiting does not linger.
+	_ = p.cmd.Process.Kill()
+}
+
+// call sends a single request and waits for its response.
+func (p *process) call(ctx context.Context, id uint64, method string, params, result any) error {
+	req := request{
+		JSONRPC: jsonRPCVersion,
+		ID:      id,
+		Method:  method,
+	}
+	if params != nil {
+		b, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = b
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	r := make(chan response, 1)
+	p.pmu.Lock()
+	if p.err != nil {
+		p.pmu.Unlock()
+		return p.err
+	}
+	p.pending[id] = r
+	p.pmu.Unlock()
+
+	p.wmu.Lock()
+	_, err = p.stdin.Write(b)
+	p.wmu.Unlock()
+	if err != nil {
+		p.forget(id)
+		return err
+	}
+
+	select {
+	case res, ok := <-r:
+		if !ok {
+			return p.exitError()
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		if result == nil || len(res.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(res.Result, result)
+	case <-ctx.Done():
+		p.forget(id)
+		return fmt.Errorf("%s: %w", method, ctx.Err())
+	}
+}
+
+func (p *process) forget(id uint64) {
+	p.pmu.Lock()
+	delete(p.pending, id)
+	p.pmu.Unlock()
+}
+
+func (p *process) exitError() error {
+	p.pmu.Lock()
+	defer p.pmu.Unlock()
+	if p.err != nil {
+		return p.err
+	}
+	return errExited
+}
+
+func (p *process) exited() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *process) kill() {
+	_ = p.stdin.Close()
+	_ = p.cmd.Process.Kill()
+	<-p.done
+}
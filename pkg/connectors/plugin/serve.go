@@ -0,0 +1,82 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Handler is implemented by plugins written in Go, see Serve.
+type Handler interface {
+	Collect(ctx context.Context) ([]Alert, error)
+	Silence(ctx context.Context, id string, duration time.Duration, user string) error
+}
+
+// Serve answers requests read from r on w until r is closed.  This is the
+// plugin side of the protocol, to be used with os.Stdin and os.Stdout.
+// Anything the plugin wants to log should go to os.Stderr.
+func Serve(ctx context.Context, name string, h Handler, r io.Reader, w io.Writer) error {
+	var wmu sync.Mutex
+	encoder := json.NewEncoder(w)
+	reply := func(res response) {
+		wmu.Lock()
+		defer wmu.Unlock()
+		_ = encoder.Encode(res)
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			reply(response{JSONRPC: jsonRPCVersion, Error: &Error{Code: ErrorParse, Message: err.Error()}})
+			continue
+		}
+
+		wg.Go(func() {
+			result, err := dispatch(ctx, name, h, req)
+			res := response{JSONRPC: jsonRPCVersion, ID: req.ID}
+			if err != nil {
+				if e, ok := err.(*Error); ok {
+					res.Error = e
+				} else {
+					res.Error = &Error{Code: ErrorInternal, Message: err.Error()}
+				}
+			} else if b, err := json.Marshal(result); err != nil {
+				res.Error = &Error{Code: ErrorInternal, Message: err.Error()}
+			} else {
+				res.Result = b
+			}
+			reply(res)
+		})
+	}
+
+	return scanner.Err()
+}
+
+func dispatch(ctx context.Context, name string, h Handler, req request) (any, error) {
+	switch req.Method {
+	case methodInitialize:
+		return InitializeResult{ProtocolVersion: ProtocolVersion, Name: name}, nil
+	case methodCollect:
+		alerts, err := h.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return CollectResult{Alerts: alerts}, nil
+	case methodSilence:
+		var params SilenceParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &Error{Code: ErrorInvalidParams, Message: err.Error()}
+		}
+		return struct{}{}, h.Silence(ctx, params.ID, time.Duration(params.Duration)*time.Second, params.User)
+	default:
+		return nil, &Error{Code: ErrorMethodNotFound, Message: "unknown method " + req.Method}
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Package probe runs synthetic checks against services without any other
+monitoring.
+
+HTTP probes check the status code, the body and the latency of a request,
+TCP probes whether a connection can be established and TLS probes whether
+the certificate is valid and not about to expire.  Only failing probes are
+reported.
+*/
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	html "html/template"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	probes []*probe
+
+	mu        sync.Mutex // Protecting firstSeen
+	firstSeen map[string]time.Time
+}
+
+type Config struct {
+	Tag string
+	// Timeout is the default timeout for a single probe.
+	Timeout time.Duration
+	Probes  []ProbeConfig
+}
+
+type ProbeConfig struct {
+	Name string
+	// Type is either `http` (default), `tcp` or `tls`.
+	Type string
+	// Address is the `host:port` to connect to for tcp and tls probes.
+	Address string
+	Timeout time.Duration
+	Labels  map[string]string
+
+	// Method defaults to `GET`, Status to any 2xx or 3xx status code.
+	Method     string
+	Status     []int
+	BodyRegex  string
+	MaxLatency time.Duration
+
+	// Certificates expiring within WarningDays or CriticalDays are reported.
+	WarningDays  int
+	CriticalDays int
+
+	// URL, authentication and TLS settings for http probes, Insecure
+	// disables the verification of the certificate chain for tls probes.
+	common.HTTPConfig
+}
+
+const (
+	typeHTTP = "http"
+	typeTCP  = "tcp"
+	typeTLS  = "tls"
+)
+
+type probe struct {
+	config    ProbeConfig
+	client    *http.Client
+	bodyRegex *regexp.Regexp
+}
+
+// result describes a failing probe.
+type result struct {
+	state       connectors.State
+	description string
+	details     string
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	c := &Connector{config: *cfg, firstSeen: make(map[string]time.Time)}
+
+	names := make(map[string]bool)
+	for _, probeConfig := range cfg.Probes {
+		if probeConfig.Type == "" {
+			probeConfig.Type = typeHTTP
+		}
+		if probeConfig.Timeout == 0 {
+			probeConfig.Timeout = cfg.Timeout
+		}
+		if probeConfig.Method == "" {
+			probeConfig.Method = http.MethodGet
+		}
+		if probeConfig.WarningDays == 0 {
+			probeConfig.WarningDays = 14
+		}
+		if probeConfig.CriticalDays == 0 {
+			probeConfig.CriticalDays = 3
+		}
+		if probeConfig.Name == "" {
+			probeConfig.Name = probeConfig.target()
+		}
+		// The state of the probes is kept by name
+		if names[probeConfig.Name] {
+			panic(fmt.Errorf("probe %s: duplicate name", probeConfig.Name))
+		}
+		names[probeConfig.Name] = true
+
+		p := &probe{config: probeConfig}
+		switch probeConfig.Type {
+		case typeHTTP:
+			p.client = probeConfig.HTTPConfig.TLSClient(&tls.Config{InsecureSkipVerify: probeConfig.Insecure})
+			p.client.Timeout = probeConfig.Timeout
+			// Redirects are reported as they are
+			p.client.CheckRedirect = func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+		case typeTCP, typeTLS:
+		default:
+			panic(fmt.Errorf("probe %s: unknown type %q", probeConfig.Name, probeConfig.Type))
+		}
+		if probeConfig.BodyRegex != "" {
+			p.bodyRegex = regexp.MustCompile(probeConfig.BodyRegex)
+		}
+		c.probes = append(c.probes, p)
+	}
+
+	return c
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+// Collect runs all probes in parallel.
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	results := make([]*result, len(c.probes))
+
+	var wg sync.WaitGroup
+	for i, p := range c.probes {
+		wg.Go(func() {
+			results[i] = p.run(ctx)
+		})
+	}
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var alerts []connectors.Alert
+	for i, p := range c.probes {
+		res := results[i]
+		if res == nil {
+			delete(c.firstSeen, p.config.Name)
+			continue
+		}
+
+		start, ok := c.firstSeen[p.config.Name]
+		if !ok {
+			start = time.Now()
+			c.firstSeen[p.config.Name] = start
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Hostname": p.config.host(),
+				"Probe":    p.config.Name,
+				"Target":   p.config.target(),
+				"Type":     "Probe",
+			},
+			Start:       start,
+			State:       res.state,
+			Description: res.description,
+			Details:     res.details,
+		}
+		for k, v := range p.config.Labels {
+			alert.Labels[k] = v
+		}
+		if p.config.Type == typeHTTP {
+			alert.Links = []html.HTML{
+				html.HTML("<a href=\"" + p.config.URL + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Probe (%d probes)", len(c.probes))
+}
+
+func (p *probe) run(ctx context.Context) *result {
+	ctx, cancel := context.WithTimeout(ctx, p.config.Timeout)
+	defer cancel()
+
+	switch p.config.Type {
+	case typeTCP:
+		return p.probeTCP(ctx)
+	case typeTLS:
+		return p.probeTLS(ctx)
+	}
+	return p.probeHTTP(ctx)
+}
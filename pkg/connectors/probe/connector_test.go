@@ -0,0 +1,205 @@
+package probe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestHTTPProbes(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if user, pass, ok := req.BasicAuth(); req.URL.Path == "/private" && (!ok || user != "jo" || pass != "secret") {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch req.URL.Path {
+		case "/broken":
+			res.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = res.Write([]byte("upstream unavailable"))
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+			_, _ = res.Write([]byte("status: ok"))
+		default:
+			_, _ = res.Write([]byte("status: ok"))
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag: "test",
+		Probes: []ProbeConfig{
+			{Name: "healthy", BodyRegex: "status: ok", HTTPConfig: common.HTTPConfig{URL: mockServer.URL + "/health"}},
+			{Name: "private", HTTPConfig: common.HTTPConfig{URL: mockServer.URL + "/private", Username: "jo", Password: "secret"}},
+			{Name: "broken", Labels: map[string]string{"Team": "shop"}, HTTPConfig: common.HTTPConfig{URL: mockServer.URL + "/broken"}},
+			{Name: "mismatch", BodyRegex: "status: degraded", HTTPConfig: common.HTTPConfig{URL: mockServer.URL + "/health"}},
+			{Name: "slow", MaxLatency: 10 * time.Millisecond, HTTPConfig: common.HTTPConfig{URL: mockServer.URL + "/slow"}},
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 3 {
+		t.Fatal("Only failing probes should be reported", alerts)
+	}
+
+	if alerts[0].Labels["Probe"] != "broken" || alerts[0].State != connectors.Critical || alerts[0].Labels["Team"] != "shop" {
+		t.Error("Unexpected status codes should be critical", alerts[0])
+	}
+	if !strings.Contains(alerts[0].Details, "upstream unavailable") {
+		t.Error("Details should contain the response", alerts[0].Details)
+	}
+	if alerts[1].Labels["Probe"] != "mismatch" || alerts[1].State != connectors.Critical {
+		t.Error("Body mismatches should be critical", alerts[1])
+	}
+	if alerts[2].Labels["Probe"] != "slow" || alerts[2].State != connectors.Warning {
+		t.Error("Slow responses should be warnings", alerts[2])
+	}
+
+	again, _ := connector.Collect(context.Background())
+	if !again[0].Start.Equal(alerts[0].Start) {
+		t.Error("Start should be kept while the probe is failing")
+	}
+}
+
+func TestTCPProbe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = closed.Close()
+	defer listener.Close()
+
+	cfg := Config{
+		Tag: "test",
+		Probes: []ProbeConfig{
+			{Name: "open", Type: "tcp", Address: listener.Addr().String()},
+			{Name: "closed", Type: "tcp", Address: closed.Addr().String()},
+		},
+	}
+
+	alerts, err := NewConnector(&cfg).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 1 || alerts[0].Labels["Probe"] != "closed" || alerts[0].Labels["Hostname"] != "127.0.0.1" {
+		t.Error("Refused connections should be reported", alerts)
+	}
+}
+
+func TestTLSProbe(t *testing.T) {
+	soon := tlsServer(t, 5*24*time.Hour)
+	later := tlsServer(t, 10*24*time.Hour)
+	fine := tlsServer(t, 90*24*time.Hour)
+	expired := tlsServer(t, -time.Minute)
+
+	cfg := Config{
+		Tag: "test",
+		Probes: []ProbeConfig{
+			{Name: "soon", Type: "tls", Address: soon, CriticalDays: 7, HTTPConfig: common.HTTPConfig{Insecure: true}},
+			{Name: "later", Type: "tls", Address: later, CriticalDays: 7, HTTPConfig: common.HTTPConfig{Insecure: true}},
+			{Name: "fine", Type: "tls", Address: fine, HTTPConfig: common.HTTPConfig{Insecure: true}},
+			{Name: "untrusted", Type: "tls", Address: fine},
+			{Name: "expired", Type: "tls", Address: expired},
+		},
+	}
+
+	alerts, err := NewConnector(&cfg).Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 4 {
+		t.Fatal("Expiring and untrusted certificates should be reported", alerts)
+	}
+	if alerts[0].Labels["Probe"] != "soon" || alerts[0].State != connectors.Critical {
+		t.Error("Certificates expiring within the critical threshold should be critical", alerts[0])
+	}
+	if alerts[1].Labels["Probe"] != "later" || alerts[1].State != connectors.Warning {
+		t.Error("Certificates expiring within the warning threshold should be warnings", alerts[1])
+	}
+	if alerts[2].Labels["Probe"] != "untrusted" || !strings.Contains(alerts[2].Details, "Not after") {
+		t.Error("Untrusted certificates should be critical", alerts[2])
+	}
+	if alerts[3].Labels["Probe"] != "expired" || !strings.HasSuffix(alerts[3].Description, "certificate expired") {
+		t.Error("Expired certificates should be reported as expired, although untrusted", alerts[3])
+	}
+}
+
+func TestDuplicateNames(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Duplicate probe names should be rejected")
+		}
+	}()
+
+	NewConnector(&Config{Probes: []ProbeConfig{
+		{Name: "shop", HTTPConfig: common.HTTPConfig{URL: "https://shop.example.com"}},
+		{Name: "shop", Type: "tls", Address: "shop.example.com:443"},
+	}})
+}
+
+// tlsServer listens with a self-signed certificate valid for the given
+// duration.
+func tlsServer(t *testing.T, validity time.Duration) string {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validity),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+
+	return listener.Addr().String()
+}
@@ -0,0 +1,162 @@
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+// maxBody limits how much of the response body is read for matching and
+// shown in the details.
+const maxBody = 64 * 1024
+
+func (p *probe) probeHTTP(ctx context.Context) *result {
+	req, err := http.NewRequestWithContext(ctx, p.config.Method, p.config.URL, nil)
+	if err != nil {
+		return &result{connectors.Critical, "Invalid request", err.Error()}
+	}
+
+	begin := time.Now()
+	res, err := p.client.Do(req)
+	if err != nil {
+		return &result{connectors.Critical, fmt.Sprintf("%s unreachable", p.config.Name), err.Error()}
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxBody))
+	latency := time.Since(begin)
+	if err != nil {
+		return &result{connectors.Critical, fmt.Sprintf("%s failed reading the response", p.config.Name), err.Error()}
+	}
+
+	details := fmt.Sprintf("%s %s: %s in %s\n\n%s", p.config.Method, p.config.URL, res.Status, latency.Round(time.Millisecond), excerpt(body))
+
+	if !p.expectedStatus(res.StatusCode) {
+		return &result{connectors.Critical, fmt.Sprintf("%s returned %s", p.config.Name, res.Status), details}
+	}
+	if p.bodyRegex != nil && !p.bodyRegex.Match(body) {
+		return &result{connectors.Critical, fmt.Sprintf("%s body does not match %s", p.config.Name, p.bodyRegex), details}
+	}
+	if p.config.MaxLatency > 0 && latency > p.config.MaxLatency {
+		return &result{connectors.Warning, fmt.Sprintf("%s slow, took %s", p.config.Name, latency.Round(time.Millisecond)), details}
+	}
+
+	return nil
+}
+
+func (p *probe) expectedStatus(status int) bool {
+	if len(p.config.Status) == 0 {
+		return status >= 200 && status < 400
+	}
+	return slices.Contains(p.config.Status, status)
+}
+
+func (p *probe) probeTCP(ctx context.Context) *result {
+	var dialer net.Dialer
+	begin := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", p.config.Address)
+	if err != nil {
+		return &result{connectors.Critical, fmt.Sprintf("%s unreachable", p.config.Name), err.Error()}
+	}
+	_ = conn.Close()
+
+	latency := time.Since(begin)
+	if p.config.MaxLatency > 0 && latency > p.config.MaxLatency {
+		return &result{connectors.Warning, fmt.Sprintf("%s slow, took %s", p.config.Name, latency.Round(time.Millisecond)), ""}
+	}
+
+	return nil
+}
+
+// probeTLS checks the certificate chain and the expiry of the leaf
+// certificate.  The chain is verified separately, to report expiring
+// certificates even if they are not trusted.  Expired certificates are
+// reported as such, although they are invalid as well.
+func (p *probe) probeTLS(ctx context.Context) *result {
+	host := p.config.host()
+	dialer := tls.Dialer{Config: &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: true,
+	}}
+
+	conn, err := dialer.DialContext(ctx, "tcp", p.config.Address)
+	if err != nil {
+		return &result{connectors.Critical, fmt.Sprintf("%s unreachable", p.config.Name), err.Error()}
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return &result{connectors.Critical, fmt.Sprintf("%s presented no certificate", p.config.Name), ""}
+	}
+	leaf := certs[0]
+
+	details := fmt.Sprintf("Subject: %s\nIssuer: %s\nNot after: %s\nDNS names: %s",
+		leaf.Subject, leaf.Issuer, leaf.NotAfter.Format(time.RFC3339), strings.Join(leaf.DNSNames, ", "))
+
+	remaining := time.Until(leaf.NotAfter)
+	if remaining <= 0 {
+		return &result{connectors.Critical, fmt.Sprintf("%s certificate expired", p.config.Name), details}
+	}
+
+	if !p.config.Insecure {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates})
+		if err != nil {
+			return &result{connectors.Critical, fmt.Sprintf("%s certificate invalid", p.config.Name), err.Error() + "\n\n" + details}
+		}
+	}
+
+	days := int(remaining.Hours() / 24)
+	switch {
+	case days < p.config.CriticalDays:
+		return &result{connectors.Critical, fmt.Sprintf("%s certificate expires in %d days", p.config.Name, days), details}
+	case days < p.config.WarningDays:
+		return &result{connectors.Warning, fmt.Sprintf("%s certificate expires in %d days", p.config.Name, days), details}
+	}
+
+	return nil
+}
+
+func (cfg ProbeConfig) target() string {
+	if cfg.Type == typeHTTP || cfg.Type == "" {
+		return cfg.URL
+	}
+	return cfg.Address
+}
+
+func (cfg ProbeConfig) host() string {
+	if cfg.Type == typeHTTP || cfg.Type == "" {
+		if u, err := url.Parse(cfg.URL); err == nil {
+			return u.Hostname()
+		}
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		return cfg.Address
+	}
+	return host
+}
+
+func excerpt(body []byte) string {
+	const maxExcerpt = 512
+	if len(body) > maxExcerpt {
+		return string(body[:maxExcerpt]) + "…"
+	}
+	return string(body)
+}
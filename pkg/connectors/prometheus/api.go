@@ -0,0 +1,61 @@
+package prometheus
+
+// https://prometheus.io/docs/prometheus/latest/querying/api/#alerts
+// https://prometheus.io/docs/prometheus/latest/querying/api/#rules
+
+type response[T any] struct {
+	Status    string `json:"status"`
+	Data      T      `json:"data"`
+	ErrorType string `json:"errorType,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type alertDiscovery struct {
+	Alerts []alert `json:"alerts"`
+}
+
+type ruleDiscovery struct {
+	Groups []ruleGroup `json:"groups"`
+}
+
+type ruleGroup struct {
+	Name  string `json:"name"`
+	File  string `json:"file"`
+	Rules []rule `json:"rules"`
+}
+
+type rule struct {
+	State       alertState        `json:"state"`
+	Name        string            `json:"name"`
+	Query       string            `json:"query"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Alerts      []alert           `json:"alerts,omitempty"`
+	Type        string            `json:"type"`
+}
+
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	State       alertState        `json:"state"`
+	ActiveAt    string            `json:"activeAt"`
+	Value       string            `json:"value"`
+	// GeneratorURL is not part of the Prometheus API, but some rulers
+	// deliver it anyway.
+	GeneratorURL string `json:"generatorURL,omitempty"`
+}
+
+type alertState = string
+
+const (
+	alertStateFiring   alertState = "firing"
+	alertStatePending  alertState = "pending"
+	alertStateInactive alertState = "inactive"
+)
+
+const (
+	severityWarning  = "warning"
+	severityError    = "error"
+	severityCritical = "critical"
+	severityNone     = "none"
+)
@@ -0,0 +1,256 @@
+/*
+Package prometheus collects firing and pending alerts directly from the rules
+API of Prometheus, Thanos Ruler or Mimir/Loki rulers.
+
+This shows alerts even when there is no Alertmanager, or when its routing
+drops them.
+*/
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	common.HTTPConfig
+	Tag     string
+	Cluster string
+
+	// Endpoint selects the API to read alerts from: `rules` (default)
+	// includes the rule query, `alerts` is available on more rulers.
+	Endpoint string
+	// PendingState is the state of pending alerts: `unknown` (default) or
+	// `warning`.
+	PendingState string
+}
+
+const (
+	endpointRules  = "rules"
+	endpointAlerts = "alerts"
+)
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = endpointRules
+	}
+
+	c := &Connector{
+		config: *cfg,
+		client: cfg.HTTPConfig.Client(),
+	}
+
+	return c
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	var sourceAlerts []ruleAlert
+	var err error
+	if c.config.Endpoint == endpointAlerts {
+		sourceAlerts, err = c.collectAlerts(ctx)
+	} else {
+		sourceAlerts, err = c.collectRules(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, sourceAlert := range sourceAlerts {
+		severity := sourceAlert.Labels["severity"]
+		if severity == severityNone {
+			continue
+		}
+
+		state, ok := c.stateFromSourceAlert(sourceAlert.alert, severity)
+		if !ok {
+			continue
+		}
+
+		var links []html.HTML
+		for _, annotation := range []string{"runbook", "runbook_url"} {
+			if link, ok := sourceAlert.Annotations[annotation]; ok {
+				links = append(links, html.HTML("<a href=\""+link+"\" target=\"_blank\" alt=\"Runbook\">📖</a>"))
+				break
+			}
+		}
+		if link := c.generatorURL(sourceAlert); link != "" {
+			links = append(links, html.HTML("<a href=\""+html.HTMLEscapeString(link)+"\" target=\"_blank\" alt=\"Home\">🏠</a>"))
+		}
+
+		var details []string
+		for _, annotation := range []string{"summary", "description"} {
+			if text, ok := sourceAlert.Annotations[annotation]; ok {
+				details = append(details, text)
+			}
+		}
+
+		labels := map[string]string{
+			"Cluster":   c.config.Cluster,
+			"Namespace": sourceAlert.Labels["namespace"],
+			"Source":    c.config.URL,
+			"Group":     sourceAlert.group,
+			"State":     sourceAlert.State,
+		}
+		for k, v := range sourceAlert.Labels {
+			labels[k] = v
+		}
+
+		start, err := time.Parse(time.RFC3339Nano, sourceAlert.ActiveAt)
+		if err != nil {
+			slog.ErrorContext(ctx, "Cannot parse", slog.Any("error", err))
+		}
+
+		alert := connectors.Alert{
+			Labels:      labels,
+			Start:       start,
+			State:       state,
+			Description: sourceAlert.Labels["alertname"],
+			Details:     strings.Join(details, "\n"),
+			Links:       links,
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Prometheus (%s)", c.config.URL)
+}
+
+// stateFromSourceAlert maps firing alerts by their severity, the same way
+// the alertmanager connector does.  Inactive alerts are skipped.
+func (c *Connector) stateFromSourceAlert(sourceAlert alert, severity string) (connectors.State, bool) {
+	switch sourceAlert.State {
+	case alertStatePending:
+		if strings.ToLower(c.config.PendingState) == "warning" {
+			return connectors.Warning, true
+		}
+		return connectors.Unknown, true
+	case alertStateFiring:
+		switch severity {
+		case severityCritical, severityError:
+			return connectors.Critical, true
+		default:
+			return connectors.Warning, true
+		}
+	}
+	return connectors.OK, false
+}
+
+// generatorURL links to the expression, preferring what the ruler delivers.
+func (c *Connector) generatorURL(sourceAlert ruleAlert) string {
+	if sourceAlert.GeneratorURL != "" {
+		return sourceAlert.GeneratorURL
+	} else if sourceAlert.query == "" {
+		return ""
+	}
+
+	q := url.Values{}
+	q.Set("g0.expr", sourceAlert.query)
+	q.Set("g0.tab", "1")
+	return c.config.URL + "/graph?" + q.Encode()
+}
+
+// ruleAlert is an alert enriched with information from its rule.
+type ruleAlert struct {
+	alert
+	group string
+	query string
+}
+
+func (c *Connector) collectRules(ctx context.Context) ([]ruleAlert, error) {
+	var result response[ruleDiscovery]
+	if err := c.get(ctx, "/api/v1/rules?type=alert", &result); err != nil {
+		return nil, err
+	}
+
+	var alerts []ruleAlert
+	for _, group := range result.Data.Groups {
+		for _, r := range group.Rules {
+			if r.Type != "alerting" {
+				continue
+			}
+			for _, a := range r.Alerts {
+				alerts = append(alerts, ruleAlert{alert: a, group: group.Name, query: r.Query})
+			}
+		}
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) collectAlerts(ctx context.Context) ([]ruleAlert, error) {
+	var result response[alertDiscovery]
+	if err := c.get(ctx, "/api/v1/alerts", &result); err != nil {
+		return nil, err
+	}
+
+	var alerts []ruleAlert
+	for _, a := range result.Data.Alerts {
+		alerts = append(alerts, ruleAlert{alert: a})
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) get(ctx context.Context, endpoint string, v any) error {
+
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, _ := io.ReadAll(res.Body)
+	buf := bytes.NewBuffer(b)
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, buf.String())
+	}
+
+	decoder := json.NewDecoder(buf)
+
+	err = decoder.Decode(v)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL),
+			slog.String("data", buf.String()),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
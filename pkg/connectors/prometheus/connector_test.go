@@ -0,0 +1,195 @@
+package prometheus
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestRulesEndpoint(t *testing.T) {
+	connector, mockServer := testConnector(Config{})
+	defer func() { mockServer.Close() }()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts, without inactive and severity none:", alerts)
+	}
+
+	firing := alerts[0]
+	if firing.State != connectors.Critical || firing.Description != "KubePodCrashLooping" {
+		t.Error("Firing alerts should be mapped by severity", firing)
+	}
+	if firing.Labels["Namespace"] != "app-prod" || firing.Labels["Cluster"] != "test" || firing.Labels["Group"] != "kubernetes-apps" {
+		t.Error("Alerts should be labeled", firing.Labels)
+	}
+
+	generatorFound := false
+	for _, link := range firing.Links {
+		if strings.Contains(string(link), "/graph?g0.expr=") {
+			generatorFound = true
+		}
+	}
+	if !generatorFound {
+		t.Error("Rule query should be linked", firing.Links)
+	}
+
+	if alerts[1].State != connectors.Unknown {
+		t.Error("Pending alerts should be unknown by default", alerts[1])
+	}
+}
+
+func TestAlertsEndpoint(t *testing.T) {
+	connector, mockServer := testConnector(Config{Endpoint: "alerts", PendingState: "warning"})
+	defer func() { mockServer.Close() }()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts", alerts)
+	}
+	if alerts[0].State != connectors.Warning {
+		t.Error("Pending alerts should be configurable as warning", alerts[0])
+	}
+	if !strings.Contains(string(alerts[1].Links[0]), "https://ruler.example.com/") {
+		t.Error("Delivered generatorURL should be linked", alerts[1].Links)
+	}
+}
+
+func testConnector(cfg Config) (*Connector, *httptest.Server) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/api/v1/rules":
+			res.WriteHeader(http.StatusOK)
+			_, _ = res.Write([]byte(mockRulesResponse))
+		case "/api/v1/alerts":
+			res.WriteHeader(http.StatusOK)
+			_, _ = res.Write([]byte(mockAlertsResponse))
+		default:
+			res.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	cfg.Tag = "test"
+	cfg.Cluster = "test"
+	cfg.HTTPConfig = common.HTTPConfig{
+		URL: mockServer.URL,
+	}
+
+	return NewConnector(&cfg), mockServer
+}
+
+const mockRulesResponse = `
+{
+  "status": "success",
+  "data": {
+    "groups": [
+      {
+        "name": "kubernetes-apps",
+        "file": "/etc/prometheus/rules/kubernetes-apps.yaml",
+        "rules": [
+          {
+            "state": "firing",
+            "name": "KubePodCrashLooping",
+            "query": "max_over_time(kube_pod_container_status_waiting_reason{reason=\"CrashLoopBackOff\"}[5m]) >= 1",
+            "duration": 900,
+            "labels": { "severity": "critical" },
+            "annotations": { "summary": "Pod is crash looping." },
+            "alerts": [
+              {
+                "labels": {
+                  "alertname": "KubePodCrashLooping",
+                  "namespace": "app-prod",
+                  "pod": "app-7f967f9945-9k8p4",
+                  "severity": "critical"
+                },
+                "annotations": {
+                  "description": "Pod app-prod/app-7f967f9945-9k8p4 is in waiting state (reason: \"CrashLoopBackOff\").",
+                  "runbook_url": "https://runbooks.prometheus-operator.dev/runbooks/kubernetes/kubepodcrashlooping",
+                  "summary": "Pod is crash looping."
+                },
+                "state": "firing",
+                "activeAt": "2024-05-02T10:11:12.123456789Z",
+                "value": "1e+00"
+              },
+              {
+                "labels": {
+                  "alertname": "KubePodCrashLooping",
+                  "namespace": "app-prod",
+                  "pod": "app-7f967f9945-abcde",
+                  "severity": "critical"
+                },
+                "annotations": {},
+                "state": "pending",
+                "activeAt": "2024-05-02T10:21:12Z",
+                "value": "1e+00"
+              }
+            ],
+            "health": "ok",
+            "type": "alerting"
+          },
+          {
+            "state": "firing",
+            "name": "Watchdog",
+            "query": "vector(1)",
+            "labels": { "severity": "none" },
+            "alerts": [
+              {
+                "labels": { "alertname": "Watchdog", "severity": "none" },
+                "annotations": {},
+                "state": "firing",
+                "activeAt": "2024-05-01T00:00:00Z",
+                "value": "1e+00"
+              }
+            ],
+            "type": "alerting"
+          },
+          {
+            "state": "inactive",
+            "name": "KubeJobFailed",
+            "query": "kube_job_failed > 0",
+            "alerts": [],
+            "type": "alerting"
+          }
+        ]
+      }
+    ]
+  }
+}
+`
+
+const mockAlertsResponse = `
+{
+  "status": "success",
+  "data": {
+    "alerts": [
+      {
+        "labels": { "alertname": "HighErrorRate", "severity": "warning" },
+        "annotations": { "summary": "Error rate is high" },
+        "state": "pending",
+        "activeAt": "2024-05-02T10:11:12Z",
+        "value": "0.2"
+      },
+      {
+        "labels": { "alertname": "HighLatency", "severity": "critical" },
+        "annotations": {},
+        "state": "firing",
+        "activeAt": "2024-05-02T10:11:12Z",
+        "value": "2.5",
+        "generatorURL": "https://ruler.example.com/graph?g0.expr=latency"
+      }
+    ]
+  }
+}
+`
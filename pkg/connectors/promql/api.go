@@ -0,0 +1,30 @@
+package promql
+
+import "encoding/json"
+
+// https://prometheus.io/docs/prometheus/latest/querying/api/#instant-queries
+
+type queryResponse struct {
+	Status    string    `json:"status"`
+	Data      queryData `json:"data"`
+	ErrorType string    `json:"errorType,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+type queryData struct {
+	ResultType resultType      `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+type sample struct {
+	Metric map[string]string `json:"metric"`
+	// Value is a tuple of a unix timestamp and the value as string.
+	Value [2]any `json:"value"`
+}
+
+type resultType = string
+
+const (
+	resultTypeVector resultType = "vector"
+	resultTypeScalar resultType = "scalar"
+)
@@ -0,0 +1,266 @@
+/*
+Package promql shows series of an ad-hoc PromQL expression which are over a
+threshold, without the need to deploy an alerting rule.
+
+see https://prometheus.io/docs/prometheus/latest/querying/api/#instant-queries
+*/
+package promql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config      Config
+	client      *http.Client
+	description *template.Template
+	details     *template.Template
+
+	mu        sync.Mutex // Protecting firstSeen
+	firstSeen map[string]time.Time
+}
+
+type Config struct {
+	common.HTTPConfig
+	Tag     string
+	Cluster string
+
+	Query    string
+	Warning  *float64
+	Critical *float64
+	// Below inverts the thresholds, alerting on values lower than them.
+	Below bool
+
+	// Description and Details are golang `text/template`s, getting a
+	// templateData.
+	Description string
+	Details     string
+	// Labels of the series which are passed through to the alert.  All
+	// labels are passed through if unset.
+	Labels []string
+}
+
+// templateData is handed to the description and details templates.
+type templateData struct {
+	Labels map[string]string
+	Value  float64
+	Query  string
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.Description == "" {
+		cfg.Description = cfg.Query
+	}
+	if cfg.Details == "" {
+		cfg.Details = "Value: {{.Value}}"
+	}
+
+	funcs := template.FuncMap{
+		"humanize": func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) },
+	}
+
+	return &Connector{
+		config:      *cfg,
+		client:      cfg.HTTPConfig.Client(),
+		description: template.Must(template.New("description").Funcs(funcs).Parse(cfg.Description)),
+		details:     template.Must(template.New("details").Funcs(funcs).Parse(cfg.Details)),
+		firstSeen:   make(map[string]time.Time),
+	}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	samples, err := c.query(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	seen := make(map[string]time.Time)
+
+	q := url.Values{}
+	q.Set("g0.expr", c.config.Query)
+	q.Set("g0.tab", "1")
+	link := c.config.URL + "/graph?" + q.Encode()
+
+	var alerts []connectors.Alert
+	for _, s := range samples {
+		value, err := s.value()
+		if err != nil {
+			slog.ErrorContext(ctx, "Cannot parse", slog.Any("value", s.Value), slog.Any("error", err))
+			continue
+		}
+
+		state := c.threshold(value)
+		if state == connectors.OK {
+			continue
+		}
+
+		// Remember when a series crossed the threshold, as the query does
+		// not know about it.
+		key := seriesKey(s.Metric)
+		c.mu.Lock()
+		start, ok := c.firstSeen[key]
+		c.mu.Unlock()
+		if !ok {
+			start = now
+		}
+		seen[key] = start
+
+		labels := map[string]string{
+			"Cluster":   c.config.Cluster,
+			"Namespace": s.Metric["namespace"],
+			"Source":    c.config.URL,
+		}
+		for k, v := range s.Metric {
+			if c.config.Labels == nil || slices.Contains(c.config.Labels, k) {
+				labels[k] = v
+			}
+		}
+
+		data := templateData{Labels: s.Metric, Value: value, Query: c.config.Query}
+		alert := connectors.Alert{
+			Labels:      labels,
+			Start:       start,
+			State:       state,
+			Description: c.render(ctx, c.description, data),
+			Details:     c.render(ctx, c.details, data),
+			Links: []html.HTML{
+				html.HTML("<a href=\"" + html.HTMLEscapeString(link) + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			},
+		}
+		alerts = append(alerts, alert)
+	}
+
+	c.mu.Lock()
+	c.firstSeen = seen
+	c.mu.Unlock()
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("PromQL (%s: %s)", c.config.URL, c.config.Query)
+}
+
+func (c *Connector) threshold(value float64) connectors.State {
+	exceeds := func(threshold *float64) bool {
+		if threshold == nil {
+			return false
+		} else if c.config.Below {
+			return value < *threshold
+		}
+		return value > *threshold
+	}
+
+	if exceeds(c.config.Critical) {
+		return connectors.Critical
+	} else if exceeds(c.config.Warning) {
+		return connectors.Warning
+	}
+	return connectors.OK
+}
+
+func (c *Connector) render(ctx context.Context, t *template.Template, data templateData) string {
+	buf := new(strings.Builder)
+	if err := t.Execute(buf, data); err != nil {
+		slog.ErrorContext(ctx, "Cannot render", slog.String("template", t.Name()), slog.Any("error", err))
+		return c.config.Query
+	}
+	return buf.String()
+}
+
+func (c *Connector) query(ctx context.Context) ([]sample, error) {
+	q := url.Values{}
+	q.Set("query", c.config.Query)
+	endpoint := "/api/v1/query?" + q.Encode()
+
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	b, _ := io.ReadAll(res.Body)
+	buf := bytes.NewBuffer(b)
+
+	decoder := json.NewDecoder(buf)
+
+	var response queryResponse
+	err = decoder.Decode(&response)
+	if err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL),
+			slog.String("data", buf.String()),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return nil, err
+	}
+
+	if response.Status != "success" {
+		return nil, fmt.Errorf("query failed: %s: %s", response.ErrorType, response.Error)
+	}
+
+	switch response.Data.ResultType {
+	case resultTypeVector:
+		var samples []sample
+		if err := json.Unmarshal(response.Data.Result, &samples); err != nil {
+			return nil, err
+		}
+		return samples, nil
+	case resultTypeScalar:
+		var value [2]any
+		if err := json.Unmarshal(response.Data.Result, &value); err != nil {
+			return nil, err
+		}
+		return []sample{{Metric: map[string]string{}, Value: value}}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported result type %s, expected vector or scalar", response.Data.ResultType)
+}
+
+func (s sample) value() (float64, error) {
+	v, ok := s.Value[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected value %v", s.Value[1])
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+func seriesKey(metric map[string]string) string {
+	var parts []string
+	for _, k := range slices.Sorted(maps.Keys(metric)) {
+		parts = append(parts, k+"="+metric[k])
+	}
+	return strings.Join(parts, ",")
+}
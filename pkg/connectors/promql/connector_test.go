@@ -0,0 +1,95 @@
+package promql
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	var query string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		query = req.URL.Query().Get("query")
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(mockResponse))
+	}))
+	defer func() { mockServer.Close() }()
+
+	warning, critical := 80.0, 90.0
+	cfg := Config{
+		Tag: "test",
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+		Query:       `100 - node_filesystem_avail_bytes / node_filesystem_size_bytes * 100`,
+		Warning:     &warning,
+		Critical:    &critical,
+		Description: `Disk {{.Labels.mountpoint}} at {{humanize .Value}}%`,
+		Labels:      []string{"instance", "mountpoint"},
+	}
+
+	connector := NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query != cfg.Query {
+		t.Error("Query should be sent", query)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts for series over the threshold", alerts)
+	}
+
+	if alerts[0].State != connectors.Critical || alerts[0].Description != "Disk /var at 95.50%" {
+		t.Error("Series should be critical", alerts[0])
+	}
+	if alerts[1].State != connectors.Warning {
+		t.Error("Series should be warning", alerts[1])
+	}
+	if alerts[0].Labels["instance"] != "db1:9100" || alerts[0].Labels["fstype"] != "" {
+		t.Error("Only configured labels should be passed through", alerts[0].Labels)
+	}
+
+	again, _ := connector.Collect(context.Background())
+	if !again[0].Start.Equal(alerts[0].Start) {
+		t.Error("Start should be kept while over the threshold")
+	}
+}
+
+func TestBelow(t *testing.T) {
+	threshold := 1.0
+	c := NewConnector(&Config{Query: "up", Critical: &threshold, Below: true})
+
+	if c.threshold(0) != connectors.Critical || c.threshold(1) != connectors.OK {
+		t.Error("Below should invert the threshold")
+	}
+}
+
+const mockResponse = `
+{
+  "status": "success",
+  "data": {
+    "resultType": "vector",
+    "result": [
+      {
+        "metric": { "instance": "db1:9100", "mountpoint": "/var", "fstype": "ext4" },
+        "value": [ 1714644672.123, "95.5" ]
+      },
+      {
+        "metric": { "instance": "db1:9100", "mountpoint": "/", "fstype": "ext4" },
+        "value": [ 1714644672.123, "85" ]
+      },
+      {
+        "metric": { "instance": "db2:9100", "mountpoint": "/", "fstype": "ext4" },
+        "value": [ 1714644672.123, "12.3" ]
+      }
+    ]
+  }
+}
+`
@@ -0,0 +1,41 @@
+package sentry
+
+// https://docs.sentry.io/api/events/list-a-projects-issues/
+
+type issue struct {
+	ID        string  `json:"id"`
+	ShortID   string  `json:"shortId"`
+	Title     string  `json:"title"`
+	Culprit   string  `json:"culprit"`
+	Permalink string  `json:"permalink"`
+	Level     level   `json:"level"`
+	Status    string  `json:"status"`
+	Count     string  `json:"count"`
+	UserCount int     `json:"userCount"`
+	FirstSeen string  `json:"firstSeen"`
+	LastSeen  string  `json:"lastSeen"`
+	Project   project `json:"project"`
+	Stats     stats   `json:"stats"`
+}
+
+type project struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// stats contains the event counts in hourly buckets as tuples of a unix
+// timestamp and the count.
+type stats struct {
+	Day [][2]int64 `json:"24h"`
+}
+
+type level = string
+
+const (
+	levelFatal   level = "fatal"
+	levelError   level = "error"
+	levelWarning level = "warning"
+	levelInfo    level = "info"
+	levelDebug   level = "debug"
+)
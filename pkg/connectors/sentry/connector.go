@@ -0,0 +1,224 @@
+package sentry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	Tag          string
+	Organization string
+	Projects     []string
+	// Query is the Sentry search query, defaults to `is:unresolved`.
+	Query string
+	// An issue is critical if the events of the last hour exceed
+	// SpikeFactor times the hourly average of the day, and at least
+	// SpikeMinEvents happened.
+	SpikeFactor    float64
+	SpikeMinEvents int64
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.URL == "" {
+		cfg.URL = "https://sentry.io"
+	}
+	if cfg.Query == "" {
+		cfg.Query = "is:unresolved"
+	}
+	if cfg.SpikeFactor == 0 {
+		cfg.SpikeFactor = 5
+	}
+	if cfg.SpikeMinEvents == 0 {
+		cfg.SpikeMinEvents = 100
+	}
+
+	return &Connector{*cfg, cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	var alerts []connectors.Alert
+
+	for _, p := range c.config.Projects {
+		issues, err := c.collectIssues(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues {
+			state := fromLevel(issue.Level)
+			spike := isSpike(issue.Stats, c.config.SpikeFactor, c.config.SpikeMinEvents)
+			if spike {
+				state = connectors.Critical
+			}
+			if state == connectors.OK {
+				continue
+			}
+
+			firstSeen, err := time.Parse(time.RFC3339, issue.FirstSeen)
+			if err != nil {
+				slog.ErrorContext(ctx, "Cannot parse", slog.Any("error", err))
+			}
+
+			alert := connectors.Alert{
+				Labels: map[string]string{
+					"Project":   issue.Project.Slug,
+					"Issue":     issue.ShortID,
+					"Level":     issue.Level,
+					"Events":    issue.Count,
+					"Users":     strconv.Itoa(issue.UserCount),
+					"FirstSeen": issue.FirstSeen,
+					"LastSeen":  issue.LastSeen,
+					"Spike":     strconv.FormatBool(spike),
+					"Source":    c.config.URL,
+					"Type":      "Issue",
+				},
+				Start:       firstSeen,
+				State:       state,
+				Description: issue.Title,
+				Details:     fmt.Sprintf("%s: %s events, %d users affected, last seen %s", issue.Culprit, issue.Count, issue.UserCount, issue.LastSeen),
+				Links: []html.HTML{
+					html.HTML("<a href=\"" + issue.Permalink + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+				},
+			}
+			alert.Silence = c.createSilencer(issue.ID)
+			alerts = append(alerts, alert)
+		}
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Sentry (%s)", c.config.URL)
+}
+
+// collectIssues collects all issues of a project matching the query,
+// following the pagination.
+func (c *Connector) collectIssues(ctx context.Context, project string) ([]issue, error) {
+	endpoint := fmt.Sprintf("/api/0/projects/%s/%s/issues/", url.PathEscape(c.config.Organization), url.PathEscape(project))
+	query := map[string]string{
+		"query":       c.config.Query,
+		"statsPeriod": "24h",
+	}
+
+	var issues []issue
+	for page := 0; page < 10; page++ {
+		body, next, err := c.get(ctx, endpoint, query)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageIssues []issue
+		err = json.NewDecoder(body).Decode(&pageIssues)
+		body.Close()
+		if err != nil {
+			slog.ErrorContext(ctx, "Cannot parse",
+				slog.String("url", c.config.URL+endpoint),
+				slog.Any("error", err))
+			return nil, err
+		}
+		issues = append(issues, pageIssues...)
+
+		if next == "" {
+			break
+		}
+		query["cursor"] = next
+	}
+
+	return issues, nil
+}
+
+// linkPattern matches the next cursor in the `Link` header, if there are more
+// results.
+//
+// see https://docs.sentry.io/api/pagination/
+var linkPattern = regexp.MustCompile(`rel="next"; results="true"; cursor="([^"]+)"`)
+
+func (c *Connector) get(ctx context.Context, endpoint string, query map[string]string) (io.ReadCloser, string, error) {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return nil, "", err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	q := req.URL.Query()
+	for k, v := range query {
+		q.Set(k, v)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		defer res.Body.Close()
+		b, _ := io.ReadAll(res.Body)
+		return nil, "", fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+	}
+
+	next := ""
+	if m := linkPattern.FindStringSubmatch(res.Header.Get("Link")); m != nil {
+		next = m[1]
+	}
+
+	return res.Body, next, nil
+}
+
+func fromLevel(l level) connectors.State {
+	switch l {
+	case levelFatal:
+		return connectors.Critical
+	case levelError, levelWarning:
+		return connectors.Warning
+	case levelInfo, levelDebug:
+		return connectors.OK
+	}
+	return connectors.Unknown
+}
+
+// isSpike compares the events in the last hour with the hourly average of
+// the rest of the day.
+func isSpike(s stats, factor float64, minEvents int64) bool {
+	if len(s.Day) < 2 {
+		return false
+	}
+
+	last := s.Day[len(s.Day)-1][1]
+	if last < minEvents {
+		return false
+	}
+
+	var sum int64
+	for _, bucket := range s.Day[:len(s.Day)-1] {
+		sum += bucket[1]
+	}
+	avg := float64(sum) / float64(len(s.Day)-1)
+
+	return float64(last) > avg*factor
+}
@@ -0,0 +1,138 @@
+package sentry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	var silence map[string]any
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPut && req.URL.Path == "/api/0/organizations/example/issues/1001/":
+			_ = json.NewDecoder(req.Body).Decode(&silence)
+			res.WriteHeader(http.StatusOK)
+		case req.URL.Path == "/api/0/projects/example/backend/issues/" && req.URL.Query().Get("cursor") == "":
+			res.Header().Set("Link", `<https://sentry.io/api/0/projects/example/backend/issues/?&cursor=100:-1:1>; rel="previous"; results="false"; cursor="100:-1:1", <https://sentry.io/api/0/projects/example/backend/issues/?&cursor=100:1:0>; rel="next"; results="true"; cursor="100:1:0"`)
+			res.WriteHeader(http.StatusOK)
+			_, _ = res.Write([]byte(mockIssuesPage1))
+		case req.URL.Path == "/api/0/projects/example/backend/issues/":
+			res.Header().Set("Link", `<https://sentry.io/api/0/projects/example/backend/issues/?&cursor=100:1:0>; rel="next"; results="false"; cursor="100:2:0"`)
+			res.WriteHeader(http.StatusOK)
+			_, _ = res.Write([]byte(mockIssuesPage2))
+		default:
+			res.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:          "test",
+		Organization: "example",
+		Projects:     []string{"backend"},
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts from both pages, without info level", alerts)
+	}
+
+	if alerts[0].State != connectors.Critical || alerts[0].Labels["Spike"] != "true" {
+		t.Error("Spiking errors should be critical", alerts[0])
+	}
+	if alerts[0].Labels["Events"] != "1337" || alerts[0].Labels["Users"] != "42" {
+		t.Error("Counts should be labels", alerts[0].Labels)
+	}
+	if alerts[1].State != connectors.Warning {
+		t.Error("Errors should be warnings", alerts[1])
+	}
+
+	if err := alerts[0].Silence(context.Background(), time.Hour, "jo"); err != nil {
+		t.Fatal(err)
+	}
+	if silence["status"] != "ignored" || silence["statusDetails"].(map[string]any)["ignoreDuration"] != 60.0 {
+		t.Error("Silencing should ignore for the duration", silence)
+	}
+}
+
+func TestSpike(t *testing.T) {
+	flat := stats{Day: [][2]int64{{0, 100}, {3600, 100}, {7200, 120}}}
+	if isSpike(flat, 5, 100) {
+		t.Error("Constant event rates are no spike")
+	}
+
+	few := stats{Day: [][2]int64{{0, 0}, {3600, 0}, {7200, 10}}}
+	if isSpike(few, 5, 100) {
+		t.Error("Spikes need a minimum of events")
+	}
+}
+
+const mockIssuesPage1 = `
+[
+  {
+    "id": "1001",
+    "shortId": "BACKEND-1A",
+    "title": "NullPointerException: Cannot invoke \"String.length()\"",
+    "culprit": "com.example.OrderService in submit",
+    "permalink": "https://sentry.io/organizations/example/issues/1001/",
+    "level": "error",
+    "status": "unresolved",
+    "count": "1337",
+    "userCount": 42,
+    "firstSeen": "2024-05-01T08:00:00Z",
+    "lastSeen": "2024-05-02T10:11:12Z",
+    "project": { "id": "2", "name": "Backend", "slug": "backend" },
+    "stats": { "24h": [ [1714640400, 2], [1714644000, 3], [1714647600, 1200] ] }
+  },
+  {
+    "id": "1002",
+    "shortId": "BACKEND-1B",
+    "title": "Slow query detected",
+    "culprit": "db",
+    "permalink": "https://sentry.io/organizations/example/issues/1002/",
+    "level": "info",
+    "status": "unresolved",
+    "count": "10",
+    "userCount": 0,
+    "firstSeen": "2024-05-01T08:00:00Z",
+    "lastSeen": "2024-05-02T10:11:12Z",
+    "project": { "id": "2", "name": "Backend", "slug": "backend" },
+    "stats": { "24h": [] }
+  }
+]
+`
+
+const mockIssuesPage2 = `
+[
+  {
+    "id": "1003",
+    "shortId": "BACKEND-1C",
+    "title": "TimeoutError: upstream timed out",
+    "culprit": "com.example.PaymentClient in charge",
+    "permalink": "https://sentry.io/organizations/example/issues/1003/",
+    "level": "error",
+    "status": "unresolved",
+    "count": "12",
+    "userCount": 3,
+    "firstSeen": "2024-05-02T08:00:00Z",
+    "lastSeen": "2024-05-02T09:00:00Z",
+    "project": { "id": "2", "name": "Backend", "slug": "backend" },
+    "stats": { "24h": [ [1714640400, 4], [1714644000, 4], [1714647600, 4] ] }
+  }
+]
+`
@@ -0,0 +1,61 @@
+package sentry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+)
+
+func (c *Connector) createSilencer(issueID string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+
+		return c.Silence(ctx, issueID, duration, user)
+	}
+}
+
+// Silence ignores the issue for the given duration.  Sentry records the owner
+// of the token as the one ignoring the issue, the user is not transmitted.
+//
+// see https://docs.sentry.io/api/events/update-an-issue/
+func (c *Connector) Silence(ctx context.Context, issueID string, duration time.Duration, _ string) error {
+	payload := map[string]interface{}{
+		"status": "ignored",
+		"statusDetails": map[string]interface{}{
+			"ignoreDuration": int(duration / time.Minute),
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := json.NewEncoder(buf).Encode(payload); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("/api/0/organizations/%s/issues/%s/", url.PathEscape(c.config.Organization), url.PathEscape(issueID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.config.URL+endpoint, buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to ignore issue, status code %d: %s", res.StatusCode, string(b))
+	}
+
+	return nil
+}
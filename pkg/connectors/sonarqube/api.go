@@ -0,0 +1,56 @@
+package sonarqube
+
+// https://next.sonarqube.com/sonarqube/web_api/api/qualitygates/project_status
+
+type projectStatusResponse struct {
+	ProjectStatus projectStatus `json:"projectStatus"`
+}
+
+type projectStatus struct {
+	Status     status      `json:"status"`
+	Conditions []condition `json:"conditions"`
+}
+
+type condition struct {
+	Status         status `json:"status"`
+	MetricKey      string `json:"metricKey"`
+	Comparator     string `json:"comparator"`
+	ErrorThreshold string `json:"errorThreshold"`
+	ActualValue    string `json:"actualValue"`
+}
+
+type status = string
+
+const (
+	statusOK    status = "OK"
+	statusWarn  status = "WARN"
+	statusError status = "ERROR"
+)
+
+// https://next.sonarqube.com/sonarqube/web_api/api/components/search
+
+type componentsResponse struct {
+	Paging     paging      `json:"paging"`
+	Components []component `json:"components"`
+}
+
+type paging struct {
+	PageIndex int `json:"pageIndex"`
+	PageSize  int `json:"pageSize"`
+	Total     int `json:"total"`
+}
+
+// https://next.sonarqube.com/sonarqube/web_api/api/components/show
+
+type componentResponse struct {
+	Component component `json:"component"`
+}
+
+type component struct {
+	Key          string `json:"key"`
+	Name         string `json:"name"`
+	AnalysisDate string `json:"analysisDate"`
+}
+
+// analysisDateLayout is the timestamp format used by SonarQube.
+const analysisDateLayout = "2006-01-02T15:04:05-0700"
@@ -0,0 +1,181 @@
+package sonarqube
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+}
+
+type Config struct {
+	Tag string
+	// Projects are project keys.  Without projects, all projects of the
+	// Organization, or all visible projects are checked.
+	Projects     []string
+	Organization string
+	common.HTTPConfig
+}
+
+func NewConnector(cfg *Config) *Connector {
+	return &Connector{*cfg, cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	projects := c.config.Projects
+	if len(projects) == 0 {
+		var err error
+		if projects, err = c.collectProjects(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var alerts []connectors.Alert
+	for _, key := range projects {
+		var status projectStatusResponse
+		if err := c.get(ctx, "/api/qualitygates/project_status", url.Values{"projectKey": {key}}, &status); err != nil {
+			return nil, err
+		}
+
+		var state connectors.State
+		switch status.ProjectStatus.Status {
+		case statusError:
+			state = connectors.Critical
+		case statusWarn:
+			state = connectors.Warning
+		default:
+			continue
+		}
+
+		var project componentResponse
+		if err := c.get(ctx, "/api/components/show", url.Values{"component": {key}}, &project); err != nil {
+			return nil, err
+		}
+
+		analysed, err := time.Parse(analysisDateLayout, project.Component.AnalysisDate)
+		if err != nil {
+			slog.ErrorContext(ctx, "Cannot parse", slog.String("analysisDate", project.Component.AnalysisDate), slog.Any("error", err))
+		}
+
+		var details []string
+		for _, cond := range status.ProjectStatus.Conditions {
+			if cond.Status == statusOK {
+				continue
+			}
+			details = append(details, fmt.Sprintf("%s: %s, required %s %s (%s)",
+				cond.MetricKey, cond.ActualValue, comparators[cond.Comparator], cond.ErrorThreshold, cond.Status))
+		}
+
+		alert := connectors.Alert{
+			Labels: map[string]string{
+				"Project": key,
+				"Name":    project.Component.Name,
+				"Status":  status.ProjectStatus.Status,
+				"Source":  c.config.URL,
+				"Type":    "QualityGate",
+			},
+			Start:       analysed,
+			State:       state,
+			Description: fmt.Sprintf("Quality gate of %s failed", project.Component.Name),
+			Details:     strings.Join(details, "\n"),
+			Links: []html.HTML{
+				html.HTML("<a href=\"" + c.config.URL + "/dashboard?id=" + url.QueryEscape(key) + "\" target=\"_blank\" alt=\"Home\">🏠</a>"),
+			},
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("SonarQube (%s)", c.config.URL)
+}
+
+// comparators describe the required value, the opposite of the comparator
+// failing the condition.
+var comparators = map[string]string{
+	"LT": ">=",
+	"GT": "<=",
+}
+
+// collectProjects lists all projects, following the pagination.
+func (c *Connector) collectProjects(ctx context.Context) ([]string, error) {
+	query := url.Values{
+		"qualifiers": {"TRK"},
+		"ps":         {"500"},
+	}
+	if c.config.Organization != "" {
+		query.Set("organization", c.config.Organization)
+	}
+
+	var projects []string
+	for page := 1; ; page++ {
+		query.Set("p", strconv.Itoa(page))
+
+		var response componentsResponse
+		if err := c.get(ctx, "/api/components/search", query, &response); err != nil {
+			return nil, err
+		}
+		for _, comp := range response.Components {
+			projects = append(projects, comp.Key)
+		}
+
+		if len(response.Components) == 0 || response.Paging.PageIndex*response.Paging.PageSize >= response.Paging.Total {
+			break
+		}
+	}
+
+	return projects, nil
+}
+
+func (c *Connector) get(ctx context.Context, endpoint string, query url.Values, v any) error {
+	slog.DebugContext(ctx, "getting alerts", slog.String("url", c.config.URL+endpoint))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.config.URL+endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.URL.RawQuery = query.Encode()
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(res.Body)
+		return fmt.Errorf("failed to get %s, status code %d: %s", endpoint, res.StatusCode, string(b))
+	}
+
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL+endpoint),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package sonarqube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer squ_example" {
+			res.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body string
+		switch req.URL.Path {
+		case "/api/components/search":
+			if req.URL.Query().Get("organization") != "example" {
+				t.Error("Projects should be searched within the organization", req.URL)
+			}
+			if req.URL.Query().Get("p") == "1" {
+				body = `{"paging": {"pageIndex": 1, "pageSize": 2, "total": 3}, "components": [{"key": "shop"}, {"key": "docs"}]}`
+			} else {
+				body = `{"paging": {"pageIndex": 2, "pageSize": 2, "total": 3}, "components": [{"key": "legacy"}]}`
+			}
+		case "/api/qualitygates/project_status":
+			body = mockStatus[req.URL.Query().Get("projectKey")]
+		case "/api/components/show":
+			key := req.URL.Query().Get("component")
+			body = `{"component": {"key": "` + key + `", "name": "Example ` + key + `", "analysisDate": "2024-05-02T10:11:12+0200"}}`
+		default:
+			res.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(body))
+	}))
+	defer func() { mockServer.Close() }()
+
+	cfg := Config{
+		Tag:          "test",
+		Organization: "example",
+		HTTPConfig: common.HTTPConfig{
+			URL:         mockServer.URL,
+			BearerToken: "squ_example",
+		},
+	}
+
+	var connector connectors.Connector = NewConnector(&cfg)
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts for failing quality gates", alerts)
+	}
+
+	if alerts[0].Labels["Project"] != "shop" || alerts[0].State != connectors.Critical {
+		t.Error("Failed quality gates should be critical", alerts[0])
+	}
+	if alerts[0].Details != "new_coverage: 45.2, required >= 80 (ERROR)" {
+		t.Error("Failing conditions should be the details", alerts[0].Details)
+	}
+	if alerts[0].Start.IsZero() {
+		t.Error("The analysis date should be the start", alerts[0])
+	}
+	if alerts[1].Labels["Project"] != "legacy" || alerts[1].State != connectors.Warning || !strings.Contains(alerts[1].Details, "(WARN)") {
+		t.Error("Quality gate warnings should be warnings", alerts[1])
+	}
+}
+
+var mockStatus = map[string]string{
+	"shop": `
+{
+  "projectStatus": {
+    "status": "ERROR",
+    "conditions": [
+      {"status": "ERROR", "metricKey": "new_coverage", "comparator": "LT", "errorThreshold": "80", "actualValue": "45.2"},
+      {"status": "OK", "metricKey": "new_duplicated_lines_density", "comparator": "GT", "errorThreshold": "3", "actualValue": "0.0"}
+    ],
+    "ignoredConditions": false
+  }
+}`,
+	"docs": `
+{
+  "projectStatus": {
+    "status": "OK",
+    "conditions": [
+      {"status": "OK", "metricKey": "new_coverage", "comparator": "LT", "errorThreshold": "80", "actualValue": "92.0"}
+    ]
+  }
+}`,
+	"legacy": `
+{
+  "projectStatus": {
+    "status": "WARN",
+    "conditions": [
+      {"status": "WARN", "metricKey": "new_security_hotspots_reviewed", "comparator": "LT", "errorThreshold": "100", "actualValue": "50.0"}
+    ]
+  }
+}`,
+}
@@ -0,0 +1,77 @@
+package zabbix
+
+import "encoding/json"
+
+// https://www.zabbix.com/documentation/current/en/manual/api
+
+type request struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+	ID      int    `json:"id"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *responseError  `json:"error,omitempty"`
+	ID      int             `json:"id"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+type problem struct {
+	EventID      string `json:"eventid"`
+	ObjectID     string `json:"objectid"`
+	Clock        string `json:"clock"`
+	Name         string `json:"name"`
+	Severity     string `json:"severity"`
+	Acknowledged string `json:"acknowledged"`
+	Suppressed   string `json:"suppressed"`
+	Tags         []tag  `json:"tags"`
+}
+
+type tag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+type trigger struct {
+	TriggerID   string `json:"triggerid"`
+	Description string `json:"description"`
+	Comments    string `json:"comments"`
+	URL         string `json:"url"`
+	Hosts       []host `json:"hosts"`
+}
+
+type host struct {
+	HostID     string      `json:"hostid"`
+	Host       string      `json:"host"`
+	Name       string      `json:"name"`
+	HostGroups []hostGroup `json:"hostgroups,omitempty"`
+}
+
+type hostGroup struct {
+	Name string `json:"name"`
+}
+
+// see https://www.zabbix.com/documentation/current/en/manual/config/triggers/severity
+const (
+	severityNotClassified = "0"
+	severityInformation   = "1"
+	severityWarning       = "2"
+	severityAverage       = "3"
+	severityHigh          = "4"
+	severityDisaster      = "5"
+)
+
+// see https://www.zabbix.com/documentation/current/en/manual/api/reference/event/acknowledge
+const (
+	actionAcknowledge = 2
+	actionAddMessage  = 4
+	actionSuppress    = 32
+)
@@ -0,0 +1,292 @@
+/*
+Package zabbix collects problems via the Zabbix JSON-RPC API.
+
+Authentication uses an API token as `BearerToken`, which requires Zabbix 6.4
+or newer.  Silencing requires Zabbix 6.2 or newer, as it suppresses the
+problem until the silence expires.
+*/
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	html "html/template"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+type Connector struct {
+	config Config
+	client *http.Client
+	id     atomic.Int32
+}
+
+type Config struct {
+	Tag string
+	common.HTTPConfig
+	// MinSeverity is the lowest severity shown, defaulting to `2` (Warning).
+	MinSeverity int
+	// HostGroups restricts problems to the given host group ids.
+	HostGroups []string
+}
+
+func NewConnector(cfg *Config) *Connector {
+	if cfg.MinSeverity == 0 {
+		cfg.MinSeverity = 2
+	}
+
+	return &Connector{config: *cfg, client: cfg.HTTPConfig.Client()}
+}
+
+func (c *Connector) Tag() string {
+	return c.config.Tag
+}
+
+func (c *Connector) Collect(ctx context.Context) ([]connectors.Alert, error) {
+	problems, err := c.collectProblems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(problems) == 0 {
+		return nil, nil
+	}
+
+	triggers, err := c.collectTriggers(ctx, problems)
+	if err != nil {
+		return nil, err
+	}
+	hosts, err := c.collectHosts(ctx, triggers)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []connectors.Alert
+	for _, p := range problems {
+		state := fromSeverity(p.Severity)
+		if state == connectors.OK {
+			continue
+		}
+
+		t := triggers[p.ObjectID]
+
+		var hostNames, groups []string
+		for _, h := range t.Hosts {
+			h = hosts[h.HostID]
+			hostNames = append(hostNames, h.Name)
+			for _, g := range h.HostGroups {
+				groups = append(groups, g.Name)
+			}
+		}
+
+		labels := map[string]string{
+			"Hostname": strings.Join(hostNames, ","),
+			"Source":   c.config.URL,
+			"groups":   strings.Join(groups, ","),
+			"Severity": severityToLabel(p.Severity),
+			"Type":     "Problem",
+		}
+		for _, tag := range p.Tags {
+			labels[tag.Tag] = tag.Value
+		}
+
+		var links []html.HTML
+		if t.URL != "" {
+			links = append(links, html.HTML("<a href=\""+html.HTMLEscapeString(t.URL)+"\" target=\"_blank\" alt=\"Runbook\">📖</a>"))
+		}
+		q := url.Values{}
+		q.Set("action", "problem.view")
+		q.Set("filter_set", "1")
+		q.Set("triggerids[]", p.ObjectID)
+		links = append(links, html.HTML("<a href=\""+html.HTMLEscapeString(c.config.URL+"/zabbix.php?"+q.Encode())+"\" target=\"_blank\" alt=\"Home\">🏠</a>"))
+
+		clock, err := strconv.ParseInt(p.Clock, 10, 64)
+		if err != nil {
+			slog.ErrorContext(ctx, "Cannot parse", slog.Any("error", err))
+		}
+
+		alert := connectors.Alert{
+			Labels:      labels,
+			Start:       time.Unix(clock, 0),
+			State:       state,
+			Description: p.Name,
+			Details:     t.Comments,
+			Links:       links,
+		}
+		alert.Silence = c.createSilencer(p.EventID)
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, nil
+}
+
+func (c *Connector) String() string {
+	return fmt.Sprintf("Zabbix (%s)", c.config.URL)
+}
+
+// collectProblems gets all unsuppressed problems.  Acknowledged problems are
+// included, as silencing acknowledges them and they have to show up again
+// once the suppression expires.
+func (c *Connector) collectProblems(ctx context.Context) ([]problem, error) {
+	var severities []int
+	for s := c.config.MinSeverity; s <= 5; s++ {
+		severities = append(severities, s)
+	}
+
+	params := map[string]any{
+		"output":     "extend",
+		"selectTags": "extend",
+		"recent":     false,
+		"suppressed": false,
+		"severities": severities,
+		"sortfield":  []string{"eventid"},
+		"sortorder":  "DESC",
+	}
+	if len(c.config.HostGroups) > 0 {
+		params["groupids"] = c.config.HostGroups
+	}
+
+	var problems []problem
+	err := c.call(ctx, "problem.get", params, &problems)
+	return problems, err
+}
+
+func (c *Connector) collectTriggers(ctx context.Context, problems []problem) (map[string]trigger, error) {
+	var ids []string
+	for _, p := range problems {
+		ids = append(ids, p.ObjectID)
+	}
+
+	params := map[string]any{
+		"triggerids":        ids,
+		"output":            []string{"triggerid", "description", "comments", "url"},
+		"selectHosts":       []string{"hostid", "host", "name"},
+		"expandComment":     true,
+		"expandDescription": true,
+	}
+
+	var triggers []trigger
+	if err := c.call(ctx, "trigger.get", params, &triggers); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]trigger)
+	for _, t := range triggers {
+		result[t.TriggerID] = t
+	}
+	return result, nil
+}
+
+func (c *Connector) collectHosts(ctx context.Context, triggers map[string]trigger) (map[string]host, error) {
+	var ids []string
+	for _, t := range triggers {
+		for _, h := range t.Hosts {
+			ids = append(ids, h.HostID)
+		}
+	}
+
+	params := map[string]any{
+		"hostids":          ids,
+		"output":           []string{"hostid", "host", "name"},
+		"selectHostGroups": []string{"name"},
+	}
+
+	var hosts []host
+	if err := c.call(ctx, "host.get", params, &hosts); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]host)
+	for _, h := range hosts {
+		result[h.HostID] = h
+	}
+	return result, nil
+}
+
+func (c *Connector) call(ctx context.Context, method string, params any, result any) error {
+	endpoint := "/api_jsonrpc.php"
+	slog.DebugContext(ctx, "calling api", slog.String("url", c.config.URL+endpoint), slog.String("method", method))
+
+	buf := new(bytes.Buffer)
+	encoder := json.NewEncoder(buf)
+	err := encoder.Encode(request{JSONRPC: "2.0", Method: method, Params: params, ID: int(c.id.Add(1))})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL+endpoint, buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json-rpc")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	b, _ := io.ReadAll(res.Body)
+
+	var rpcResponse response
+	if err := json.Unmarshal(b, &rpcResponse); err != nil {
+		slog.ErrorContext(ctx, "Cannot parse",
+			slog.String("url", c.config.URL),
+			slog.String("data", string(b)),
+			slog.Any("status", res.StatusCode),
+			slog.Any("error", err))
+		return err
+	}
+	if rpcResponse.Error != nil {
+		return fmt.Errorf("%s failed: %s %s", method, rpcResponse.Error.Message, rpcResponse.Error.Data)
+	}
+
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(rpcResponse.Result, result)
+}
+
+func fromSeverity(severity string) connectors.State {
+	switch severity {
+	case severityNotClassified:
+		return connectors.Unknown
+	case severityInformation:
+		return connectors.OK
+	case severityWarning, severityAverage:
+		return connectors.Warning
+	case severityHigh, severityDisaster:
+		return connectors.Critical
+	}
+	return connectors.Unknown
+}
+
+func severityToLabel(severity string) string {
+	switch severity {
+	case severityNotClassified:
+		return "Not classified"
+	case severityInformation:
+		return "Information"
+	case severityWarning:
+		return "Warning"
+	case severityAverage:
+		return "Average"
+	case severityHigh:
+		return "High"
+	case severityDisaster:
+		return "Disaster"
+	}
+	return "Unknown"
+}
@@ -0,0 +1,167 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/connectors/common"
+)
+
+func TestConnector(t *testing.T) {
+	connector, requests, mockServer := testConnector()
+	defer func() { mockServer.Close() }()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatal("There should be alerts", alerts)
+	}
+
+	alert := alerts[0]
+	if alert.State != connectors.Critical || alert.Description != "Disk space is low on db1" {
+		t.Error("Problem should be mapped", alert)
+	}
+	if alert.Labels["Hostname"] != "db1" || alert.Labels["groups"] != "Databases,Linux servers" || alert.Labels["scope"] != "capacity" {
+		t.Error("Host, groups and tags should be labels", alert.Labels)
+	}
+	if alerts[1].State != connectors.Warning {
+		t.Error("Average should be a warning", alerts[1])
+	}
+
+	if err := alert.Silence(context.Background(), time.Hour, "jo"); err != nil {
+		t.Fatal(err)
+	}
+	ack := (*requests)["event.acknowledge"]
+	if ack["action"].(float64) != actionAcknowledge|actionAddMessage|actionSuppress {
+		t.Error("Silencing should acknowledge and suppress", ack)
+	}
+	if ack["eventids"].([]any)[0] != "1001" || ack["suppress_until"].(float64) < float64(time.Now().Unix()) {
+		t.Error("Silencing should suppress the event until expiry", ack)
+	}
+}
+
+func TestExpiredSuppression(t *testing.T) {
+	connector, requests, mockServer := testConnector()
+	defer func() { mockServer.Close() }()
+
+	alerts, err := connector.Collect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	params := (*requests)["problem.get"]
+	if _, ok := params["acknowledged"]; ok || params["suppressed"] != false {
+		t.Error("Only suppressed problems should be hidden", params)
+	}
+	if len(alerts) != 2 || alerts[1].Description != "High CPU utilization on web1" {
+		t.Error("Acknowledged problems should show up again once their suppression expired", alerts)
+	}
+}
+
+func testConnector() (*Connector, *map[string]map[string]any, *httptest.Server) {
+	requests := make(map[string]map[string]any)
+
+	mockServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		var r struct {
+			Method string         `json:"method"`
+			Params map[string]any `json:"params"`
+			ID     int            `json:"id"`
+		}
+		_ = json.NewDecoder(req.Body).Decode(&r)
+		requests[r.Method] = r.Params
+
+		result := map[string]string{
+			"problem.get":       mockProblems,
+			"trigger.get":       mockTriggers,
+			"host.get":          mockHosts,
+			"event.acknowledge": `{"eventids": ["1001"]}`,
+		}[r.Method]
+
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": ` + result + `}`))
+	}))
+
+	cfg := Config{
+		Tag: "test",
+		HTTPConfig: common.HTTPConfig{
+			URL: mockServer.URL,
+		},
+	}
+
+	return NewConnector(&cfg), &requests, mockServer
+}
+
+const mockProblems = `
+[
+  {
+    "eventid": "1001",
+    "source": "0",
+    "object": "0",
+    "objectid": "201",
+    "clock": "1714644672",
+    "ns": "0",
+    "r_eventid": "0",
+    "correlationid": "0",
+    "userid": "0",
+    "name": "Disk space is low on db1",
+    "acknowledged": "0",
+    "severity": "4",
+    "suppressed": "0",
+    "tags": [ { "tag": "scope", "value": "capacity" } ]
+  },
+  {
+    "eventid": "1002",
+    "objectid": "202",
+    "clock": "1714644000",
+    "name": "High CPU utilization on web1",
+    "acknowledged": "1",
+    "severity": "3",
+    "suppressed": "0",
+    "tags": []
+  },
+  {
+    "eventid": "1003",
+    "objectid": "202",
+    "clock": "1714644000",
+    "name": "Just informing",
+    "acknowledged": "0",
+    "severity": "1",
+    "suppressed": "0",
+    "tags": []
+  }
+]
+`
+
+const mockTriggers = `
+[
+  {
+    "triggerid": "201",
+    "description": "Disk space is low on db1",
+    "comments": "Free disk space is less than 10% on volume /var",
+    "url": "https://wiki.example.com/disk",
+    "hosts": [ { "hostid": "10084", "host": "db1", "name": "db1" } ]
+  },
+  {
+    "triggerid": "202",
+    "description": "High CPU utilization on web1",
+    "comments": "",
+    "url": "",
+    "hosts": [ { "hostid": "10085", "host": "web1", "name": "web1" } ]
+  }
+]
+`
+
+const mockHosts = `
+[
+  { "hostid": "10084", "host": "db1", "name": "db1", "hostgroups": [ { "name": "Databases" }, { "name": "Linux servers" } ] },
+  { "hostid": "10085", "host": "web1", "name": "web1", "hostgroups": [ { "name": "Linux servers" } ] }
+]
+`
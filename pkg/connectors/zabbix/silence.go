@@ -0,0 +1,31 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/synyx/tuwat/pkg/connectors"
+	"github.com/synyx/tuwat/pkg/version"
+)
+
+func (c *Connector) createSilencer(eventID string) connectors.SilencerFunc {
+
+	return func(ctx context.Context, duration time.Duration, user string) error {
+
+		return c.Silence(ctx, eventID, duration, user)
+	}
+}
+
+// Silence acknowledges the event and suppresses it until the duration has
+// passed.
+func (c *Connector) Silence(ctx context.Context, eventID string, duration time.Duration, user string) error {
+	params := map[string]any{
+		"eventids":       []string{eventID},
+		"action":         actionAcknowledge | actionAddMessage | actionSuppress,
+		"message":        fmt.Sprintf("%s: silenced via %s", user, version.Info.Application),
+		"suppress_until": time.Now().Add(duration).Unix(),
+	}
+
+	return c.call(ctx, "event.acknowledge", params, nil)
+}
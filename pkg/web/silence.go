@@ -7,16 +7,30 @@ import (
 )
 
 func (h *WebHandler) silence(w http.ResponseWriter, req *http.Request) {
+	alertId := common.GetField(req, 0)
+
+	h.aggregator.Silence(req.Context(), alertId, silencingUser(req))
+
+	h.afterSilencing(w, req)
+}
+
+func (h *WebHandler) unsilence(w http.ResponseWriter, req *http.Request) {
+	alertId := common.GetField(req, 0)
+
+	h.aggregator.Unsilence(req.Context(), alertId, silencingUser(req))
 
+	h.afterSilencing(w, req)
+}
+
+func silencingUser(req *http.Request) string {
 	user := "jo"
 	if hdr := req.Header.Get("X-Auth-Request-User"); hdr != "" {
 		user = hdr
 	}
+	return user
+}
 
-	alertId := common.GetField(req, 0)
-
-	h.aggregator.Silence(req.Context(), alertId, user)
-
+func (h *WebHandler) afterSilencing(w http.ResponseWriter, req *http.Request) {
 	if req.Header.Get("Accept") == "text/vnd.turbo-stream.html" {
 		dashboardName := common.GetField(req, 0)
 		renderer := h.partialRenderer(req, "_stream.gohtml", "alerts.gohtml")
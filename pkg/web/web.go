@@ -84,6 +84,7 @@ func NewWebHandler(cfg *config.Config, aggregator *aggregation.Aggregator) *WebH
 		common.NewRoute("GET", "/ws/(?:alerts/([^/]+))?", websocket.Handler(handler.wsalerts).ServeHTTP),
 		common.NewRoute("GET", "/sse/(?:alerts/([^/]+))?", handler.ssealerts),
 		common.NewRoute("POST", "/alerts/([^/]+)/silence", handler.silence),
+		common.NewRoute("POST", "/alerts/([^/]+)/unsilence", handler.unsilence),
 	}
 
 	return handler